@@ -25,16 +25,17 @@ import (
 	"github.com/dolthub/go-mysql-server/enginetest/scriptgen/setup"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/plan"
-	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/dolthub/go-mysql-server/sql/planutil"
 )
 
 type JoinPlanTest struct {
-	q       string
-	types   []plan.JoinType
-	indexes []string
-	exp     []sql.Row
-	order   []string
-	skipOld bool
+	q        string
+	types    []plan.JoinType
+	indexes  []string
+	nullable []bool
+	exp      []sql.Row
+	order    []string
+	skipOld  bool
 }
 
 var JoinPlanningTests = []struct {
@@ -844,6 +845,10 @@ where u in (select * from rec);`,
 				q:     "select /*+ HASH_JOIN(xy,uv) */ 1 from xy join uv on x = u",
 				types: []plan.JoinType{plan.JoinTypeHash},
 			},
+			{
+				q:     "select /*+ INDEX_HASH_JOIN(xy,uv) */ 1 from xy join uv on x = u",
+				types: []plan.JoinType{plan.JoinTypeIndexHash},
+			},
 			{
 				q:     "select /*+ JOIN_ORDER(a,b,c) HASH_JOIN(a,b) HASH_JOIN(b,c) */ 1 from xy a join uv b on a.x = b.u join xy c on b.u = c.x",
 				types: []plan.JoinType{plan.JoinTypeHash, plan.JoinTypeHash},
@@ -919,6 +924,373 @@ join uv d on d.u = c.x`,
 			},
 		},
 	},
+	{
+		name: "negative join hint",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int, key(v));",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// With no hint at all, uv's primary key on u makes a lookup join the default choice.
+				q:     "select /*+ JOIN_ORDER(xy,uv) */ 1 from xy join uv on x = u",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+			{
+				// Forbidding the lookup join flips the plan to the next-best legal operator instead of erroring.
+				q:     "select /*+ JOIN_ORDER(xy,uv) NO_LOOKUP_JOIN(xy,uv) */ 1 from xy join uv on x = u",
+				types: []plan.JoinType{plan.JoinTypeHash},
+			},
+			{
+				q:     "select /*+ JOIN_ORDER(xy,uv) NO_LOOKUP_JOIN(xy,uv) NO_HASH_JOIN(xy,uv) */ 1 from xy join uv on x = u",
+				types: []plan.JoinType{plan.JoinTypeInner},
+			},
+			{
+				// NO_INDEX_JOIN is the narrower alias for NO_LOOKUP_JOIN and produces the same fallback.
+				q:     "select /*+ JOIN_ORDER(xy,uv) NO_INDEX_JOIN(xy,uv) */ 1 from xy join uv on x = u",
+				types: []plan.JoinType{plan.JoinTypeHash},
+			},
+			{
+				// SEMI_JOIN pins the algorithm, but NO_SEMI_JOIN vetoes it query-wide, so the plan falls back to
+				// the next-best legal operator instead of honoring the now-forbidden positive hint.
+				q:     "select /*+ SEMI_JOIN(xy,scalarSubq0) NO_SEMI_JOIN */ 1 from xy where x in (select u from uv)",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+			{
+				// A negative hint naming a join algorithm the optimizer wasn't going to choose for this pair
+				// leaves the plan unchanged.
+				q:     "select /*+ JOIN_ORDER(xy,uv) NO_MERGE_JOIN(xy,uv) */ 1 from xy join uv on x = u",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+		},
+	},
+	{
+		name: "merge cte hint",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int, index y_idx(y));",
+			"CREATE table uv (u int primary key, v int, index v_idx(v));",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// With no hint, the CTE body runs as an opaque node joined against xy with a hash join.
+				q:     "with cte as (select * from uv) select /*+ JOIN_ORDER(xy,cte) */ * from xy join cte on x = cte.u",
+				types: []plan.JoinType{plan.JoinTypeHash},
+			},
+			{
+				// MERGE(cte) splices cte's body into the outer query, letting uv's own index participate in
+				// join selection the same as it would if the query had been written without the CTE at all.
+				q:     "with cte as (select * from uv) select /*+ JOIN_ORDER(xy,cte) MERGE(cte) */ * from xy join cte on x = cte.u",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+			{
+				// Referenced twice: MERGE is ignored (with a warning) and the CTE still runs as an opaque node.
+				q: `
+with cte as (select * from uv)
+select /*+ JOIN_ORDER(xy,cte) MERGE(cte) */ * from xy join cte on x = cte.u
+where x in (select u from cte)`,
+				types: []plan.JoinType{plan.JoinTypeHash, plan.JoinTypeHash},
+			},
+			{
+				// Recursive CTEs can't be merged into the enclosing join; MERGE is ignored (with a warning).
+				q: `
+with recursive cte(u) as (select 1 union select u+1 from cte where u < 3)
+select /*+ JOIN_ORDER(xy,cte) MERGE(cte) */ * from xy join cte on x = cte.u`,
+				types: []plan.JoinType{plan.JoinTypeHash},
+			},
+		},
+	},
+	{
+		name: "session variables disable join operators",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int, index y_idx(y));",
+			"CREATE table uv (u int primary key, v int, index v_idx(v));",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+			"SET SESSION gms_opt_enable_hash_join = OFF;",
+		},
+		tests: []JoinPlanTest{
+			{
+				// Left unhinted, a query whose only sensible plans are a hash join or a plain nested loop now
+				// falls back to the nested loop, since gms_opt_enable_hash_join is OFF for this session.
+				q:     "select /*+ JOIN_ORDER(xy,uv) */ 1 from xy join uv on x + 1 = u - 1",
+				types: []plan.JoinType{plan.JoinTypeInner},
+			},
+			{
+				// An explicit HASH_JOIN hint can't override the session-wide flag either.
+				q:     "select /*+ JOIN_ORDER(xy,uv) HASH_JOIN(xy,uv) */ 1 from xy join uv on x + 1 = u - 1",
+				types: []plan.JoinType{plan.JoinTypeInner},
+			},
+			{
+				q:     "select /*+ JOIN_ORDER(xy,uv) */ 1 from xy join uv on x = u",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+		},
+	},
+	{
+		name: "session variable disables lookup join",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int, index y_idx(y));",
+			"CREATE table uv (u int primary key, v int, index v_idx(v));",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+			"SET SESSION gms_opt_enable_lookup_join = OFF;",
+		},
+		tests: []JoinPlanTest{
+			{
+				// With lookup joins disabled, a join that would otherwise pick a lookup join on uv's index
+				// falls back to a hash join instead.
+				q:     "select /*+ JOIN_ORDER(xy,uv) */ 1 from xy join uv on x = u",
+				types: []plan.JoinType{plan.JoinTypeHash},
+			},
+		},
+	},
+	{
+		name: "tidb-style session variables disable join operators",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int, key(v));",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+			"SET SESSION tidb_opt_enable_hash_join = OFF;",
+		},
+		tests: []JoinPlanTest{
+			{
+				// With hash joins disabled session-wide, the optimizer falls back to the lookup join
+				// instead of erroring.
+				q:     "select /*+ JOIN_ORDER(xy,uv) */ 1 from xy join uv on xy.x = uv.u",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+		},
+	},
+	{
+		name: "tidb-style session variable disables lookup join",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int, key(v));",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+			"SET SESSION tidb_opt_enable_lookup_join = OFF;",
+		},
+		tests: []JoinPlanTest{
+			{
+				// With lookup joins disabled session-wide, the optimizer falls back to a hash join.
+				q:     "select /*+ JOIN_ORDER(xy,uv) */ 1 from xy join uv on xy.x = uv.u",
+				types: []plan.JoinType{plan.JoinTypeHash},
+			},
+		},
+	},
+	{
+		name: "outer join nullability propagation",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int);",
+			"CREATE table ab (a int primary key, b int);",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1);",
+			"insert into ab values (0,1), (1,1);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// The right side of a LEFT JOIN can be substituted with NULLs, so both of uv's columns
+				// must report Nullable even though uv declares u as a NOT NULL primary key.
+				q:        "select xy.x, uv.u, uv.v from xy left join uv on xy.x = uv.u",
+				nullable: []bool{false, true, true},
+			},
+			{
+				// Nullability introduced by the LEFT JOIN must persist through a further INNER JOIN layered
+				// on top of it.
+				q:        "select xy.x, uv.u, ab.a from (xy left join uv on xy.x = uv.u) join ab on xy.x = ab.a",
+				nullable: []bool{false, true, false},
+			},
+			{
+				// A WHERE filter that rejects NULLs on uv.v converts the LEFT JOIN back to inner-join
+				// semantics, so the nullability override no longer applies.
+				q:        "select xy.x, uv.u, uv.v from xy left join uv on xy.x = uv.u where uv.v is not null",
+				nullable: []bool{false, false, false},
+			},
+		},
+	},
+	{
+		name: "adjacent filter fusion with tautology elimination",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int);",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// The duplicated x>0 conjunct and the 1=1 tautology both collapse away, leaving a single
+				// fused filter rather than a stack of redundant ones.
+				q: "select x from xy where 1=1 and x>0 and x>0 order by x",
+				exp: []sql.Row{
+					{1},
+					{2},
+					{3},
+				},
+			},
+			{
+				// A filter that's unconditionally FALSE prunes the whole join to an empty result instead
+				// of a no-op scan over both tables.
+				q:     "select xy.x from xy join uv on xy.x = uv.u where false",
+				types: []plan.JoinType{},
+				exp:   []sql.Row{},
+			},
+		},
+	},
+	{
+		name: "cross join elimination via WHERE-clause equi-predicates",
+		setup: []string{
+			"CREATE table ta (x int primary key, y int);",
+			"CREATE table tb (x int primary key, y int);",
+			"CREATE table tc (x int primary key, y int);",
+			"insert into ta values (0,0), (1,1);",
+			"insert into tb values (0,0), (1,1);",
+			"insert into tc values (0,0), (1,1);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// Written with comma-joins and WHERE-clause equalities, this must compile to the same
+				// two-inner-join tree as the equivalent explicit JOIN ... ON form, not a pair of cross
+				// joins plus a leftover top-level filter.
+				q:     "select ta.x from ta, tb, tc where ta.x = tb.x and tb.y = tc.y",
+				types: []plan.JoinType{plan.JoinTypeInner, plan.JoinTypeInner},
+			},
+			{
+				// td has no equi-predicate tying it to the other three tables, so it's left as a trailing
+				// cross join alongside the connected ta/tb/tc component.
+				q:     "select ta.x from ta, tb, tc, (select x as x, y as y from ta) td where ta.x = tb.x and tb.y = tc.y",
+				types: []plan.JoinType{plan.JoinTypeCross, plan.JoinTypeInner, plan.JoinTypeInner},
+			},
+		},
+	},
+	{
+		name: "auto-injected IS NOT NULL on null-rejecting join keys",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int, v int);",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (NULL,2), (NULL,3);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// uv.u is nullable and the inner join null-rejects both sides, so the rows with a NULL key
+				// are dropped whether or not the synthesized IS NOT NULL predicate fired - this just pins
+				// the result is unaffected by the rewrite.
+				q: "select x, u from xy join uv on xy.x = uv.u order by x",
+				exp: []sql.Row{
+					{0, 0},
+					{1, 1},
+				},
+			},
+			{
+				// The left side of a LEFT JOIN is the preserved side and must not have a NOT NULL filter
+				// injected onto it - a NULL xy.x row would otherwise be wrongly dropped.
+				q: "select x, u from xy left join uv on xy.x = uv.u order by x",
+				exp: []sql.Row{
+					{0, 0},
+					{1, 1},
+					{2, nil},
+					{3, nil},
+				},
+			},
+		},
+	},
+	{
+		name: "index hash join wins over lookup join on a duplicate-heavy outer side",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int, key(v));",
+			// xy's join column repeats the same value many times over, so a plain lookup join would
+			// re-issue the identical index lookup on uv once per outer row.
+			"insert into xy values (1,0), (2,0), (3,0), (4,0), (5,0), (6,0), (7,0), (8,0);",
+			"insert into uv values (0,1);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// INDEX_HASH_JOIN batches the repeated outer key into a single probe against uv's index
+				// instead of one lookup per outer row.
+				q:     "select /*+ JOIN_ORDER(xy,uv) INDEX_HASH_JOIN(xy,uv) */ 1 from xy join uv on xy.y = uv.u",
+				types: []plan.JoinType{plan.JoinTypeIndexHash},
+			},
+		},
+	},
+	{
+		name: "negative join hint falls back to the next-best operator",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int, key(v));",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// With no hint, an equi-join against uv's primary key is cheapest as a lookup join.
+				q:     "select /*+ JOIN_ORDER(xy,uv) */ 1 from xy join uv on xy.x = uv.u",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+			{
+				// NO_LOOKUP_JOIN forbids the lookup join, so the optimizer falls back to a hash join instead
+				// of refusing to plan the query.
+				q:     "select /*+ JOIN_ORDER(xy,uv) NO_LOOKUP_JOIN(xy,uv) */ 1 from xy join uv on xy.x = uv.u",
+				types: []plan.JoinType{plan.JoinTypeHash},
+			},
+			{
+				// NO_HASH_JOIN forbids the hash join, so the optimizer falls back to the lookup join that's
+				// otherwise cheapest.
+				q:     "select /*+ JOIN_ORDER(xy,uv) NO_HASH_JOIN(xy,uv) */ 1 from xy join uv on xy.x = uv.u",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+			{
+				// NO_INDEX_JOIN is the narrower form of NO_LOOKUP_JOIN and forbids the same single-value
+				// index lookup, falling back to a hash join here too.
+				q:     "select /*+ JOIN_ORDER(xy,uv) NO_INDEX_JOIN(xy,uv) */ 1 from xy join uv on xy.x = uv.u",
+				types: []plan.JoinType{plan.JoinTypeHash},
+			},
+			{
+				// A query-wide NO_HASH_JOIN (no table arguments) disallows the operator for every join in
+				// the query, not just one pair.
+				q:     "select /*+ JOIN_ORDER(xy,uv) NO_HASH_JOIN */ 1 from xy join uv on xy.x = uv.u",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+			},
+		},
+	},
+	{
+		name: "straight join hint",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int, key(v));",
+			"CREATE table ab (a int primary key, b int);",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+			"insert into ab values (0,2), (1,2), (2,2), (3,1);",
+		},
+		tests: []JoinPlanTest{
+			{
+				// JOIN_ORDER pins the same left-to-right order, for comparison with STRAIGHT_JOIN below.
+				q:     "select /*+ JOIN_ORDER(ab, uv, xy) */ 1 from ab join uv on ab.a = uv.u join xy on uv.u = xy.x",
+				types: []plan.JoinType{plan.JoinTypeLookup, plan.JoinTypeLookup},
+				order: []string{"ab", "uv", "xy"},
+			},
+			{
+				// STRAIGHT_JOIN freezes the as-written left-to-right FROM order instead of letting the DP search
+				// reorder around uv's index, while still choosing the best physical operator for each fixed pair.
+				q:     "select /*+ STRAIGHT_JOIN */ 1 from ab join uv on ab.a = uv.u join xy on uv.u = xy.x",
+				types: []plan.JoinType{plan.JoinTypeLookup, plan.JoinTypeHash},
+				order: []string{"ab", "uv", "xy"},
+			},
+			{
+				// The SELECT STRAIGHT_JOIN keyword form has the same effect as the hint.
+				q:     "select straight_join 1 from ab join uv on ab.a = uv.u join xy on uv.u = xy.x",
+				types: []plan.JoinType{plan.JoinTypeLookup, plan.JoinTypeHash},
+				order: []string{"ab", "uv", "xy"},
+			},
+		},
+	},
 	{
 		// This is a regression test for https://github.com/dolthub/go-mysql-server/pull/1889.
 		// We should always prefer a more specific index over a less specific index for lookups.
@@ -1417,11 +1789,59 @@ func TestJoinPlanning(t *testing.T, harness Harness) {
 				if tt.order != nil {
 					evalJoinOrder(t, harness, e, tt.q, tt.order, tt.skipOld)
 				}
+				if tt.nullable != nil {
+					evalOuterJoinNullability(t, harness, e, tt.q, tt.nullable)
+				}
 			}
 		})
 	}
 }
 
+// JoinPlanShapeTests exercises evalJoinPlan: unlike the "types" field on JoinPlanTest, which only checks the
+// flat list of operators in plan order, these assert the exact shape of the join tree - which operator joins
+// which pair of inputs - catching regressions like "this query used to pick a hash join and now picks a
+// nested loop" over the same table pair within a larger plan.
+var JoinPlanShapeTests = []struct {
+	name   string
+	setup  []string
+	q      string
+	expect *JoinPlanShape
+}{
+	{
+		name: "lookup join nested under a hash join",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int);",
+			"CREATE table uv (u int primary key, v int, key(v));",
+			"CREATE table ab (a int primary key, b int);",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+			"insert into ab values (0,1), (1,1), (2,2), (3,2);",
+		},
+		q: "select /*+ JOIN_ORDER(ab, xy, uv) HASH_JOIN(ab, xy) LOOKUP_JOIN(xy, uv) */ 1 " +
+			"from ab join xy on ab.a = xy.x join uv on xy.x = uv.u",
+		expect: &JoinPlanShape{
+			Op:   plan.JoinTypeHash,
+			Left: &JoinPlanShape{Table: "ab"},
+			Right: &JoinPlanShape{
+				Op:    plan.JoinTypeLookup,
+				Left:  &JoinPlanShape{Table: "xy"},
+				Right: &JoinPlanShape{Table: "uv", Index: "PRIMARY"},
+			},
+		},
+	},
+}
+
+func TestJoinPlanShapes(t *testing.T, harness Harness) {
+	for _, tt := range JoinPlanShapeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			harness.Setup([]setup.SetupScript{setup.MydbData[0], tt.setup})
+			e := mustNewEngine(t, harness)
+			defer e.Close()
+			evalJoinPlan(t, harness, e, tt.q, tt.expect)
+		})
+	}
+}
+
 func evalJoinTypeTest(t *testing.T, harness Harness, e *sqle.Engine, tt JoinPlanTest) {
 	t.Run(tt.q+" join types", func(t *testing.T) {
 		if tt.skipOld {
@@ -1492,61 +1912,156 @@ func evalJoinCorrectness(t *testing.T, harness Harness, e *sqle.Engine, name, q
 	})
 }
 
+// collectJoinTypes delegates to planutil.CollectJoins, the public, supported version of this traversal.
 func collectJoinTypes(n sql.Node) []plan.JoinType {
 	var types []plan.JoinType
-	transform.Inspect(n, func(n sql.Node) bool {
-		if n == nil {
-			return true
-		}
-		j, ok := n.(*plan.JoinNode)
-		if ok {
-			types = append(types, j.Op)
-		}
+	for _, j := range planutil.CollectJoins(n) {
+		types = append(types, j.Op)
+	}
+	return types
+}
 
-		if ex, ok := n.(sql.Expressioner); ok {
-			for _, e := range ex.Expressions() {
-				transform.InspectExpr(e, func(e sql.Expression) bool {
-					sq, ok := e.(*plan.Subquery)
-					if !ok {
-						return false
-					}
-					types = append(types, collectJoinTypes(sq.Query)...)
-					return false
-				})
-			}
+// evalOuterJoinNullability asserts that analyzing q produces a result schema whose Nullable flags match
+// wantNullable exactly, column for column.
+func evalOuterJoinNullability(t *testing.T, harness Harness, e *sqle.Engine, q string, wantNullable []bool) {
+	t.Run(q+" outer join nullability", func(t *testing.T) {
+		ctx := NewContext(harness)
+		ctx = ctx.WithQuery(q)
+
+		a, err := e.AnalyzeQuery(ctx, q)
+		require.NoError(t, err)
+
+		var gotNullable []bool
+		for _, c := range a.Schema() {
+			gotNullable = append(gotNullable, c.Nullable)
 		}
-		return true
+		require.Equal(t, wantNullable, gotNullable, fmt.Sprintf("unexpected plan:\n%s", sql.DebugString(a)))
 	})
-	return types
 }
 
+// collectIndexes delegates to planutil.CollectIndexAccesses, the public, supported version of this
+// traversal.
 func collectIndexes(n sql.Node) []sql.Index {
 	var indexes []sql.Index
-	transform.Inspect(n, func(n sql.Node) bool {
-		if n == nil {
-			return true
+	for _, a := range planutil.CollectIndexAccesses(n) {
+		indexes = append(indexes, a.Index)
+	}
+	return indexes
+}
+
+// JoinPlanShape describes the expected shape of an analyzed plan's join tree: either a leaf naming the table
+// (and, for an indexed access, the index) at that position, or an internal node naming the physical Op
+// MySQL's EXPLAIN would report there, with Left and Right describing its children recursively. evalJoinPlan
+// uses it to assert both join order AND physical operator choice in one call, which evalJoinOrder alone
+// cannot - "my query used to pick a hash join and now picks a nested loop" only shows up here.
+type JoinPlanShape struct {
+	Op    plan.JoinType
+	Table string
+	Index string
+	Left  *JoinPlanShape
+	Right *JoinPlanShape
+}
+
+// evalJoinPlan asserts that q analyzes to a plan whose join tree matches expected exactly, reporting a
+// diff-style failure with both the expected shape and sql.DebugString(a) on mismatch.
+func evalJoinPlan(t *testing.T, harness Harness, e *sqle.Engine, q string, expected *JoinPlanShape) {
+	t.Run(q+" join plan shape", func(t *testing.T) {
+		ctx := NewContext(harness)
+		ctx = ctx.WithQuery(q)
+
+		a, err := e.AnalyzeQuery(ctx, q)
+		require.NoError(t, err)
+
+		if !matchJoinShape(a, expected) {
+			t.Fatalf("join plan shape mismatch for query %q\nexpected shape:\n%s\nactual plan:\n%s",
+				q, describeJoinShape(expected, 0), sql.DebugString(a))
 		}
-		access, ok := n.(*plan.IndexedTableAccess)
-		if ok {
-			indexes = append(indexes, access.Index())
-			return true
+	})
+}
+
+// matchJoinShape compares n - descending through single-child wrapper nodes and subqueries the same way
+// collectJoinTypes/collectIndexes do - against shape, recursing into both children when shape names an
+// internal join node.
+func matchJoinShape(n sql.Node, shape *JoinPlanShape) bool {
+	if shape == nil {
+		return true
+	}
+	if shape.Left != nil || shape.Right != nil {
+		j := findJoinNode(n)
+		if j == nil || j.Op != shape.Op {
+			return false
 		}
+		return matchJoinShape(j.Left(), shape.Left) && matchJoinShape(j.Right(), shape.Right)
+	}
+	name, index := leafTableInfo(n)
+	if shape.Table != "" && name != shape.Table {
+		return false
+	}
+	if shape.Index != "" && index != shape.Index {
+		return false
+	}
+	return true
+}
 
-		if ex, ok := n.(sql.Expressioner); ok {
-			for _, e := range ex.Expressions() {
-				transform.InspectExpr(e, func(e sql.Expression) bool {
-					sq, ok := e.(*plan.Subquery)
-					if !ok {
-						return false
-					}
-					indexes = append(indexes, collectIndexes(sq.Query)...)
-					return false
-				})
+// findJoinNode returns the first *plan.JoinNode reachable from n via planutil's usual descent (children and
+// subquery expressions), or nil if there isn't one.
+func findJoinNode(n sql.Node) *plan.JoinNode {
+	var found *plan.JoinNode
+	planutil.WalkPlan(n, func(node sql.Node, _ planutil.Path) bool {
+		if found != nil {
+			return false
+		}
+		if j, ok := node.(*plan.JoinNode); ok {
+			found = j
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// leafTableInfo returns the table name - and, for an indexed access, the index ID - of the first table
+// source reachable from n.
+func leafTableInfo(n sql.Node) (name, index string) {
+	planutil.WalkPlan(n, func(node sql.Node, _ planutil.Path) bool {
+		if name != "" {
+			return false
+		}
+		switch t := node.(type) {
+		case *plan.IndexedTableAccess:
+			name = t.Name()
+			if idx := t.Index(); idx != nil {
+				index = idx.ID()
 			}
+			return false
+		case *plan.TableAlias:
+			name = t.Name()
+			return false
+		case *plan.ResolvedTable:
+			name = t.Name()
+			return false
 		}
 		return true
 	})
-	return indexes
+	return name, index
+}
+
+// describeJoinShape renders shape as an indented tree, for failure messages.
+func describeJoinShape(shape *JoinPlanShape, depth int) string {
+	indent := strings.Repeat("  ", depth)
+	if shape == nil {
+		return indent + "<nil>\n"
+	}
+	if shape.Left != nil || shape.Right != nil {
+		s := fmt.Sprintf("%s%s\n", indent, shape.Op)
+		s += describeJoinShape(shape.Left, depth+1)
+		s += describeJoinShape(shape.Right, depth+1)
+		return s
+	}
+	if shape.Index != "" {
+		return fmt.Sprintf("%s%s (index %s)\n", indent, shape.Table, shape.Index)
+	}
+	return fmt.Sprintf("%s%s\n", indent, shape.Table)
 }
 
 func evalJoinOrder(t *testing.T, harness Harness, e *sqle.Engine, q string, exp []string, skipOld bool) {
@@ -1562,21 +2077,7 @@ func evalJoinOrder(t *testing.T, harness Harness, e *sqle.Engine, q string, exp
 	})
 }
 
+// collectJoinOrder delegates to planutil.CollectTableOrder, the public, supported version of this traversal.
 func collectJoinOrder(n sql.Node) []string {
-	order := []string{}
-
-	switch n := n.(type) {
-	case *plan.JoinNode:
-		order = append(order, collectJoinOrder(n.Left())...)
-		order = append(order, collectJoinOrder(n.Right())...)
-	case *plan.TableAlias:
-		order = append(order, n.Name())
-	default:
-		children := n.Children()
-		for _, c := range children {
-			order = append(order, collectJoinOrder(c)...)
-		}
-	}
-
-	return order
+	return planutil.CollectTableOrder(n)
 }
@@ -0,0 +1,273 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// Selection is a single field of a GraphQL selection set, already parsed
+// out of query text. Compile walks a tree of Selections rather than query
+// text directly; the text-level lexer/parser is expected to produce this
+// shape before calling Compile, which keeps the SQL lowering logic (the
+// part that actually needs to match this repo's plan node shapes)
+// independent of GraphQL grammar details.
+type Selection struct {
+	// Field is the GraphQL field name, resolved against the schema's
+	// Entity/Edge mapping.
+	Field string
+	// Children are nested selections; a non-empty Children list means
+	// Field is an edge to another entity rather than a scalar column.
+	Children []Selection
+	// Where, if non-empty, lowers to a plan.Filter over this field's rows.
+	// Clauses are ANDed together.
+	Where []WhereClause
+	// OrderBy, if non-empty, lowers to a plan.Sort over this field's rows.
+	OrderBy []OrderByField
+	// Limit, if non-zero, lowers to a plan.Limit over this field's rows.
+	Limit int
+}
+
+// WhereClause is a single `where` argument on a GraphQL field: Field compared against Value using Op.
+type WhereClause struct {
+	// Field is the GraphQL field name, resolved against the entity's Fields map the same way a selected
+	// scalar field is.
+	Field string
+	// Op is the comparison: "eq" (the default when empty), "ne", "gt", "gte", "lt", or "lte".
+	Op string
+	// Value is the literal to compare Field against.
+	Value interface{}
+}
+
+// OrderByField is a single `order_by` argument on a GraphQL field.
+type OrderByField struct {
+	// Field is the GraphQL field name, resolved the same way as WhereClause.Field.
+	Field string
+	// Desc sorts descending instead of the default ascending.
+	Desc bool
+}
+
+// Compile lowers a root Selection against an entity of the schema into a
+// sql.Node tree: each selection set becomes a Project over a chain of
+// LeftJoins rooted at the entity's table scan, with nested selection sets
+// aggregated into the parent row via JSON_ARRAYAGG(JSON_OBJECT(...)) so a
+// single query returns the full nested response.
+func Compile(schema *Schema, rootType string, sel Selection) (sql.Node, error) {
+	entity, ok := schema.Entities[rootType]
+	if !ok {
+		return nil, fmt.Errorf("graphql: unknown entity %q", rootType)
+	}
+	return compileEntity(schema, entity, sel)
+}
+
+func compileEntity(schema *Schema, entity Entity, sel Selection) (sql.Node, error) {
+	table, err := resolveTable(schema, entity.Table)
+	if err != nil {
+		return nil, err
+	}
+	var node sql.Node = plan.NewResolvedTable(table, schema.Database, nil)
+
+	if len(sel.Where) > 0 {
+		filter, err := whereExpression(entity, sel.Where)
+		if err != nil {
+			return nil, err
+		}
+		node = plan.NewFilter(filter, node)
+	}
+
+	var projections []sql.Expression
+	var scalarSeen bool
+	for _, child := range sel.Children {
+		edge, isEdge := findEdge(entity, child.Field)
+		if !isEdge {
+			col := entity.Fields[child.Field]
+			if col == "" {
+				col = child.Field
+			}
+			projections = append(projections, expression.NewUnresolvedColumn(col))
+			scalarSeen = true
+			continue
+		}
+
+		childEntity, ok := schema.Entities[edgeTargetType(schema, edge)]
+		if !ok {
+			return nil, fmt.Errorf("graphql: edge %q has no matching entity", edge.Field)
+		}
+		childNode, err := compileEntity(schema, childEntity, child)
+		if err != nil {
+			return nil, err
+		}
+
+		joinCond := expression.NewEquals(
+			expression.NewUnresolvedQualifiedColumn(entity.Table, edge.ParentColumn),
+			expression.NewUnresolvedQualifiedColumn(edge.ChildTable, edge.ChildColumn),
+		)
+		node = plan.NewLeftOuterJoin(node, childNode, joinCond)
+
+		// Nested rows are folded into a single JSON array per parent row so the response shape matches the
+		// GraphQL selection set; JSON_OBJECT is given the child entity's own selected scalar fields as
+		// alternating name/value arguments, the same fields compileEntity would otherwise have projected.
+		projections = append(projections, expression.NewAlias(child.Field,
+			expression.NewUnresolvedFunction("json_arrayagg", true, nil,
+				expression.NewUnresolvedFunction("json_object", false, nil, jsonObjectArgs(childEntity, child.Children)...))))
+	}
+
+	var result sql.Node
+	if !scalarSeen && len(projections) == 0 {
+		// No explicit fields selected: project every column of the entity.
+		result = node
+	} else {
+		result = plan.NewProject(projections, node)
+	}
+
+	if len(sel.OrderBy) > 0 {
+		result = plan.NewSort(orderByFields(entity, sel.OrderBy), result)
+	}
+	if sel.Limit > 0 {
+		result = plan.NewLimit(expression.NewLiteral(int64(sel.Limit), sql.Int64), result)
+	}
+
+	return result, nil
+}
+
+// jsonObjectArgs returns the alternating name/value arguments JSON_OBJECT needs to build one object per row
+// out of entity's scalar fields selected in children - the same fields compileEntity projects for a
+// top-level selection set, but flattened into a single function call's argument list instead.
+func jsonObjectArgs(entity Entity, children []Selection) []sql.Expression {
+	var args []sql.Expression
+	for _, child := range children {
+		if _, isEdge := findEdge(entity, child.Field); isEdge {
+			continue
+		}
+		col := entity.Fields[child.Field]
+		if col == "" {
+			col = child.Field
+		}
+		args = append(args, expression.NewLiteral(child.Field, sql.LongText),
+			expression.NewUnresolvedQualifiedColumn(entity.Table, col))
+	}
+	return args
+}
+
+// whereExpression ANDs together the comparisons described by clauses, each resolved against entity's own
+// table and Fields mapping.
+func whereExpression(entity Entity, clauses []WhereClause) (sql.Expression, error) {
+	var expr sql.Expression
+	for _, c := range clauses {
+		col := entity.Fields[c.Field]
+		if col == "" {
+			col = c.Field
+		}
+		left := expression.NewUnresolvedQualifiedColumn(entity.Table, col)
+		right := expression.NewLiteral(c.Value, literalTypeFor(c.Value))
+
+		var cmp sql.Expression
+		switch c.Op {
+		case "", "eq":
+			cmp = expression.NewEquals(left, right)
+		case "ne":
+			cmp = expression.NewNot(expression.NewEquals(left, right))
+		case "gt":
+			cmp = expression.NewGreaterThan(left, right)
+		case "gte":
+			cmp = expression.NewGreaterThanOrEqual(left, right)
+		case "lt":
+			cmp = expression.NewLessThan(left, right)
+		case "lte":
+			cmp = expression.NewLessThanOrEqual(left, right)
+		default:
+			return nil, fmt.Errorf("graphql: unsupported where operator %q on field %q", c.Op, c.Field)
+		}
+
+		if expr == nil {
+			expr = cmp
+		} else {
+			expr = expression.NewAnd(expr, cmp)
+		}
+	}
+	return expr, nil
+}
+
+// orderByFields resolves each OrderByField against entity's table and Fields mapping into a sql.SortField.
+func orderByFields(entity Entity, fields []OrderByField) []sql.SortField {
+	sortFields := make([]sql.SortField, len(fields))
+	for i, f := range fields {
+		col := entity.Fields[f.Field]
+		if col == "" {
+			col = f.Field
+		}
+		order := sql.Ascending
+		if f.Desc {
+			order = sql.Descending
+		}
+		sortFields[i] = sql.SortField{
+			Column: expression.NewUnresolvedQualifiedColumn(entity.Table, col),
+			Order:  order,
+		}
+	}
+	return sortFields
+}
+
+// literalTypeFor picks the sql.Type matching value's Go type, so WhereClause.Value round-trips through
+// expression.NewLiteral without the caller having to specify a SQL type alongside every value.
+func literalTypeFor(value interface{}) sql.Type {
+	switch value.(type) {
+	case int, int8, int16, int32, int64:
+		return sql.Int64
+	case uint, uint8, uint16, uint32, uint64:
+		return sql.Uint64
+	case float32, float64:
+		return sql.Float64
+	case bool:
+		return sql.Boolean
+	default:
+		return sql.LongText
+	}
+}
+
+func findEdge(entity Entity, field string) (Edge, bool) {
+	for _, e := range entity.Edges {
+		if e.Field == field {
+			return e, true
+		}
+	}
+	return Edge{}, false
+}
+
+// edgeTargetType finds the entity type whose backing table matches the
+// edge's child table.
+func edgeTargetType(schema *Schema, edge Edge) string {
+	for typ, e := range schema.Entities {
+		if e.Table == edge.ChildTable {
+			return typ
+		}
+	}
+	return ""
+}
+
+func resolveTable(schema *Schema, name string) (sql.Table, error) {
+	t, ok, err := schema.Database.GetTableInsensitive(sql.NewEmptyContext(), name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrTableNotFound.New(name)
+	}
+	return t, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphql compiles GraphQL queries into go-mysql-server's sql/plan
+// node trees (plan.ResolvedTable, plan.Project, plan.LeftOuterJoin, and so
+// on), the same nodes the analyzer produces from parsed SQL, so a GraphQL
+// client is served by the same engine as MySQL wire clients. It does not
+// implement a general-purpose GraphQL server; it only covers the subset of
+// the language needed to map a selection set onto SQL table scans and joins.
+package graphql
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Edge describes a foreign-key relationship between two GraphQL entity
+// types: a parent-id column on Parent joined to a child-id column on Child,
+// optionally through a many-to-many junction table.
+type Edge struct {
+	// Field is the GraphQL field name of this edge on the parent type.
+	Field string
+	// ParentColumn is the column on the parent's table referenced by the edge.
+	ParentColumn string
+	// ChildTable/ChildColumn locate the matching rows on the child side.
+	ChildTable  string
+	ChildColumn string
+	// Junction, if non-empty, names a many-to-many junction table joining
+	// ParentColumn to ChildColumn rather than a direct foreign key. Compile
+	// does not read Junction: it always joins ParentColumn directly to
+	// ChildTable.ChildColumn.
+	Junction string
+	// List is true if this edge can return more than one child row.
+	List bool
+}
+
+// Entity maps a GraphQL object type to a backing SQL table.
+type Entity struct {
+	// Type is the GraphQL type name, e.g. "Author".
+	Type string
+	// Table is the backing sql table name.
+	Table string
+	// Fields maps GraphQL field names to SQL column names. Fields absent
+	// from this map are assumed to share a name with their column.
+	Fields map[string]string
+	// Edges lists the relationships reachable from this entity's selection
+	// set.
+	Edges []Edge
+}
+
+// Schema is a GraphQL-to-SQL mapping: one Entity per GraphQL object type
+// that can be queried or joined into.
+type Schema struct {
+	Entities map[string]Entity
+	// Database is the sql.Database all entity tables are resolved against.
+	Database sql.Database
+}
+
+// NewSchema constructs an empty Schema targeting db.
+func NewSchema(db sql.Database) *Schema {
+	return &Schema{Entities: map[string]Entity{}, Database: db}
+}
+
+// WithEntity registers an Entity and returns the Schema for chaining.
+func (s *Schema) WithEntity(e Entity) *Schema {
+	s.Entities[e.Type] = e
+	return s
+}
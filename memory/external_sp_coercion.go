@@ -0,0 +1,129 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// coercionKey identifies one registered coercion rule: a caller-side
+// sql.Type binding to a Go reflect.Type a procedure's Go function expects
+// for that parameter.
+type coercionKey struct {
+	from sql.Type
+	to   reflect.Type
+}
+
+// coercionFunc converts a value already matching from's Go representation
+// into the to type the coercionKey it's registered under names.
+type coercionFunc func(interface{}) (interface{}, error)
+
+// CoercionRegistry holds the argument-coercion rules ExternalStoredProcedureProvider.Call
+// applies once an overload has been chosen: an argument whose Go value
+// isn't assignable to its parameter's Go type is converted by the rule
+// registered for its (sql.Type, reflect.Type) pair. The rules are not
+// used to choose between overloads, and carry no cost or preference order.
+type CoercionRegistry struct {
+	rules map[coercionKey]coercionFunc
+}
+
+// NewCoercionRegistry returns a CoercionRegistry pre-populated with this
+// package's default rules: types.Float64 (carrying a DECIMAL value) to
+// float64, types.Blob to []byte or string, and types.Datetime to time.Time
+// or *time.Time. Rules are matched on the exact sql.Type value, so other
+// binary or text types, such as VARBINARY(n) or TEXT, have no default rule.
+func NewCoercionRegistry() *CoercionRegistry {
+	r := &CoercionRegistry{rules: map[coercionKey]coercionFunc{}}
+
+	r.RegisterCoercion(types.Float64, reflect.TypeOf(float64(0)), func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case decimal.Decimal:
+			f, _ := v.Float64()
+			return f, nil
+		case float64:
+			return v, nil
+		default:
+			return nil, fmt.Errorf("cannot coerce %T to float64", v)
+		}
+	})
+
+	r.RegisterCoercion(types.Blob, reflect.TypeOf([]byte(nil)), func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			return []byte(v), nil
+		default:
+			return nil, fmt.Errorf("cannot coerce %T to []byte", v)
+		}
+	})
+	r.RegisterCoercion(types.Blob, reflect.TypeOf(""), func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case []byte:
+			return string(v), nil
+		case string:
+			return v, nil
+		default:
+			return nil, fmt.Errorf("cannot coerce %T to string", v)
+		}
+	})
+
+	r.RegisterCoercion(types.Datetime, reflect.TypeOf(time.Time{}), func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case time.Time:
+			return v, nil
+		default:
+			return nil, fmt.Errorf("cannot coerce %T to time.Time", v)
+		}
+	})
+	r.RegisterCoercion(types.Datetime, reflect.TypeOf(&time.Time{}), func(v interface{}) (interface{}, error) {
+		t, ok := v.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("cannot coerce %T to *time.Time", v)
+		}
+		return &t, nil
+	})
+
+	return r
+}
+
+// RegisterCoercion adds or replaces the rule used to bind a value of type
+// from onto a Go parameter of type to.
+func (r *CoercionRegistry) RegisterCoercion(from sql.Type, to reflect.Type, fn func(interface{}) (interface{}, error)) {
+	r.rules[coercionKey{from: from, to: to}] = fn
+}
+
+// Coerce converts val, whose SQL type is from, into the representation a
+// parameter of Go type to expects. It returns val unchanged, with ok
+// false, when from's Go representation already satisfies to and no
+// registered rule is needed.
+func (r *CoercionRegistry) Coerce(from sql.Type, to reflect.Type, val interface{}) (coerced interface{}, ok bool, err error) {
+	if val != nil && reflect.TypeOf(val).AssignableTo(to) {
+		return val, false, nil
+	}
+	fn, registered := r.rules[coercionKey{from: from, to: to}]
+	if !registered {
+		return nil, false, fmt.Errorf("no coercion registered from %s to %s", from, to)
+	}
+	coerced, err = fn(val)
+	return coerced, true, err
+}
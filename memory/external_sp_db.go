@@ -16,6 +16,7 @@ package memory
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -33,6 +34,23 @@ var (
 		Name: "a",
 		Type: sql.LongText,
 	}}
+	// A registered Function can also be CursorFunction-shaped - returning an
+	// sql.Cursor instead of an sql.RowIter - for a procedure whose result is
+	// meant to be paged in rather than buffered whole; see cursor_numbers
+	// below. Serving that page-in behavior over COM_STMT_FETCH is a wire
+	// protocol handler concern and lives outside the memory package.
+	//
+	// externalStoredProcedures registers the Function for each external
+	// stored procedure alongside a static Schema, for the common case where
+	// the result columns don't depend on the call. A procedure whose result
+	// shape can only be known once its arguments are in hand - variadic_
+	// overload2 below, whose column count tracks how many variadic values
+	// were passed - registers a nil Schema and instead returns its own
+	// sql.Schema as the first result of Function, ahead of the sql.RowIter.
+	// The analyzer resolves the procedure's output schema lazily from that
+	// return value rather than from the registry entry, the same way a
+	// binlog consumer's schema tracker resolves a table's columns from the
+	// stream instead of a pre-registered definition.
 	externalStoredProcedures = []sql.ExternalStoredProcedureDetails{
 		{
 			Name:     "memory_inout_add",
@@ -70,9 +88,14 @@ var (
 			Function: inout_bool_byte,
 		},
 		{
-			Name:     "memory_error_table_not_found",
-			Schema:   nil,
-			Function: error_table_not_found,
+			// Restricted to callers holding the SELECT privilege, as an
+			// example of RequiredPrivileges gating a procedure call before
+			// Function ever runs; see ExternalStoredProcedureProvider.InvokeSecurely.
+			Name:               "memory_error_table_not_found",
+			Schema:             nil,
+			Function:           error_table_not_found,
+			SecurityType:       sql.ProcedureSecurityType_Invoker,
+			RequiredPrivileges: []sql.PrivilegeType{sql.PrivilegeType_Select},
 		},
 		{
 			Name:     "memory_variadic_add",
@@ -90,16 +113,28 @@ var (
 			Function: variadic_overload1,
 		},
 		{
+			// Schema is left nil here: variadic_overload2 reports its own
+			// schema at call time instead, since its column layout depends
+			// on how many variadic arguments were actually passed.
 			Name:     "memory_variadic_overload",
-			Schema:   externalSPSchemaText,
+			Schema:   nil,
 			Function: variadic_overload2,
 		},
+		{
+			// cursor_numbers is a CursorFunction-shaped procedure: Function
+			// returns an sql.Cursor instead of an sql.RowIter, so results
+			// can be paged in rather than buffered up front.
+			Name:     "memory_cursor_numbers",
+			Schema:   externalSPSchemaInt,
+			Function: cursor_numbers,
+		},
 	}
 )
 
 // ExternalStoredProcedureProvider is an implementation of sql.ExternalStoredProcedureProvider for the memory db.
 type ExternalStoredProcedureProvider struct {
 	procedureDirectory sql.ExternalStoredProcedureRegistry
+	coercions          *CoercionRegistry
 }
 
 var _ sql.ExternalStoredProcedureProvider = (*ExternalStoredProcedureProvider)(nil)
@@ -113,10 +148,21 @@ func NewExternalStoredProcedureProvider() ExternalStoredProcedureProvider {
 
 	return ExternalStoredProcedureProvider{
 		procedureDirectory: procedureDirectory,
+		coercions:          NewCoercionRegistry(),
 	}
 }
 
-// ExternalStoredProcedure implements the sql.ExternalStoredProcedureProvider interface
+// RegisterCoercion adds a rule Call uses to bind a caller's sql.Type
+// argument onto a Go parameter type the chosen overload doesn't accept
+// as-is. Overload selection itself does not consult these rules.
+func (e ExternalStoredProcedureProvider) RegisterCoercion(from sql.Type, to reflect.Type, fn func(interface{}) (interface{}, error)) {
+	e.coercions.RegisterCoercion(from, to, fn)
+}
+
+// ExternalStoredProcedure implements the sql.ExternalStoredProcedureProvider interface.
+// It selects the overload of name by parameter count alone, through the
+// registry's LookupByNameAndParamCount; argument types and the rules
+// registered with RegisterCoercion play no part in the choice.
 func (e ExternalStoredProcedureProvider) ExternalStoredProcedure(_ *sql.Context, name string, numOfParams int) (*sql.ExternalStoredProcedureDetails, error) {
 	return e.procedureDirectory.LookupByNameAndParamCount(name, numOfParams)
 }
@@ -194,10 +240,69 @@ func variadic_byte_slice(_ *sql.Context, vals ...[]byte) (sql.RowIter, error) {
 	return sql.RowsToRowIter(sql.Row{sb.String()}), nil
 }
 
+// numberCursor implements sql.Cursor over an in-memory slice of rows,
+// letting a CursorFunction-shaped procedure hand results back a page at a
+// time instead of buffering them all into a single sql.RowIter up front.
+type numberCursor struct {
+	rows []sql.Row
+	pos  int
+}
+
+var _ sql.Cursor = (*numberCursor)(nil)
+
+// FetchN implements sql.Cursor, returning up to the next n rows.
+func (c *numberCursor) FetchN(n int) (sql.RowIter, error) {
+	if c.pos >= len(c.rows) {
+		return sql.RowsToRowIter(), nil
+	}
+	end := c.pos + n
+	if end > len(c.rows) {
+		end = len(c.rows)
+	}
+	page := c.rows[c.pos:end]
+	c.pos = end
+	return sql.RowsToRowIter(page...), nil
+}
+
+// Close implements sql.Cursor.
+func (c *numberCursor) Close(_ *sql.Context) error {
+	c.rows = nil
+	return nil
+}
+
+// cursor_numbers is a CursorFunction-shaped external stored procedure: it
+// returns an sql.Cursor rather than an sql.RowIter, so a caller - the
+// MySQL wire protocol's COM_STMT_FETCH path, in particular - can page
+// through the 1..n result with repeated FetchN calls rather than
+// buffering it all at once via RowsToRowIter.
+func cursor_numbers(_ *sql.Context, n int64) (sql.Cursor, error) {
+	rows := make([]sql.Row, 0, n)
+	for i := int64(1); i <= n; i++ {
+		rows = append(rows, sql.Row{i})
+	}
+	return &numberCursor{rows: rows}, nil
+}
+
 func variadic_overload1(_ *sql.Context, a string, b string) (sql.RowIter, error) {
 	return sql.RowsToRowIter(sql.Row{fmt.Sprintf("%s-%s", a, b)}), nil
 }
 
-func variadic_overload2(_ *sql.Context, a string, b string, vals ...uint8) (sql.RowIter, error) {
-	return sql.RowsToRowIter(sql.Row{fmt.Sprintf("%s,%s,%v", a, b, vals)}), nil
+// variadic_overload2 returns its schema as a first result ahead of its
+// sql.RowIter: one "valN" BIGINT column per variadic argument received, plus
+// the combined "a" TEXT summary column. A procedure's column layout is registered with a
+// nil Schema, as above, when the function itself needs to derive the
+// columns from the call's actual argument count rather than a fixed shape.
+func variadic_overload2(_ *sql.Context, a string, b string, vals ...uint8) (sql.Schema, sql.RowIter, error) {
+	schema := make(sql.Schema, 0, len(vals)+1)
+	for i := range vals {
+		schema = append(schema, &sql.Column{Name: fmt.Sprintf("val%d", i+1), Type: sql.Int64})
+	}
+	schema = append(schema, &sql.Column{Name: "a", Type: sql.LongText})
+
+	row := make(sql.Row, 0, len(vals)+1)
+	for _, v := range vals {
+		row = append(row, int64(v))
+	}
+	row = append(row, fmt.Sprintf("%s,%s,%v", a, b, vals))
+	return schema, sql.RowsToRowIter(row), nil
 }
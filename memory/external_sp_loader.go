@@ -0,0 +1,156 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// externalStoredProceduresSymbol is the name a Go plugin must export a
+// []sql.ExternalStoredProcedureDetails under for LoadGoPlugin to find it.
+const externalStoredProceduresSymbol = "ExternalStoredProcedures"
+
+// LoadGoPlugin opens the shared object at path with plugin.Open and
+// registers the []sql.ExternalStoredProcedureDetails it exports under the
+// symbol name "ExternalStoredProcedures", the same shape externalStoredProcedures
+// in external_sp_db.go is declared with. It lets a server load procedures discovered at
+// runtime instead of only the ones compiled into this package.
+func (e ExternalStoredProcedureProvider) LoadGoPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("loading external stored procedure plugin %q: %w", path, err)
+	}
+	sym, err := p.Lookup(externalStoredProceduresSymbol)
+	if err != nil {
+		return fmt.Errorf("plugin %q does not export %s: %w", path, externalStoredProceduresSymbol, err)
+	}
+	procs, ok := sym.(*[]sql.ExternalStoredProcedureDetails)
+	if !ok {
+		return fmt.Errorf("plugin %q: %s has type %T, expected *[]sql.ExternalStoredProcedureDetails", path, externalStoredProceduresSymbol, sym)
+	}
+	for _, esp := range *procs {
+		e.procedureDirectory.Register(esp)
+	}
+	return nil
+}
+
+// LoadWasmModule compiles and instantiates the WASM module at path with
+// wazero, reflects over its exported functions, and registers one
+// sql.ExternalStoredProcedureDetails per export, named
+// "<module file name without extension>_<export name>". An export with more
+// than one result, or with a result that isn't i32/i64/f32/f64, makes
+// LoadWasmModule return an error; exports registered before that one stay
+// registered. This is deliberately limited
+// to numeric-only signatures: a WASM export has no way to describe a
+// sql.RowIter or variadic/pointer (INOUT) parameters the way a Go plugin
+// function can, so a module wanting those richer shapes should be loaded
+// as a Go plugin instead.
+func (e ExternalStoredProcedureProvider) LoadWasmModule(ctx *sql.Context, path string) error {
+	wasmBytes, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading WASM module %q: %w", path, err)
+	}
+
+	runtimeCtx := context.Background()
+	r := wazero.NewRuntime(runtimeCtx)
+	mod, err := r.Instantiate(runtimeCtx, wasmBytes)
+	if err != nil {
+		return fmt.Errorf("instantiating WASM module %q: %w", path, err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	for exportName, def := range mod.ExportedFunctionDefinitions() {
+		fn := mod.ExportedFunction(exportName)
+		if fn == nil {
+			continue
+		}
+		schema, err := wasmResultSchema(def.ResultTypes())
+		if err != nil {
+			return fmt.Errorf("WASM module %q export %q: %w", path, exportName, err)
+		}
+		e.procedureDirectory.Register(sql.ExternalStoredProcedureDetails{
+			Name:     fmt.Sprintf("%s_%s", name, exportName),
+			Schema:   schema,
+			Function: wasmProcedureFunc(fn, def.ParamTypes(), def.ResultTypes()),
+		})
+	}
+	return nil
+}
+
+// wasmResultSchema translates a WASM export's result types into the single-
+// column sql.Schema an external stored procedure reports, since a WASM
+// function returns at most one value.
+func wasmResultSchema(results []api.ValueType) (sql.Schema, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+	if len(results) > 1 {
+		return nil, fmt.Errorf("WASM exports with multiple results are not supported")
+	}
+	var typ sql.Type
+	switch results[0] {
+	case api.ValueTypeI32, api.ValueTypeI64:
+		typ = sql.Int64
+	case api.ValueTypeF32, api.ValueTypeF64:
+		typ = sql.Float64
+	default:
+		return nil, fmt.Errorf("unsupported WASM result type %v", results[0])
+	}
+	return sql.Schema{&sql.Column{Name: "a", Type: typ}}, nil
+}
+
+// wasmProcedureFunc adapts a wazero api.Function, whose parameters and
+// result are untyped uint64 lanes, into the Function shape the rest of
+// externalStoredProcedures uses: a Go function taking *sql.Context plus one
+// int64 per WASM parameter and returning a single-row sql.RowIter. A float
+// result is decoded to a float64, matching the Float64 column
+// wasmResultSchema reports for it.
+func wasmProcedureFunc(fn api.Function, params, results []api.ValueType) interface{} {
+	return func(ctx *sql.Context, args ...int64) (sql.RowIter, error) {
+		if len(args) != len(params) {
+			return nil, fmt.Errorf("expected %d arguments, got %d", len(params), len(args))
+		}
+		lanes := make([]uint64, len(args))
+		for i, a := range args {
+			lanes[i] = uint64(a)
+		}
+		out, err := fn.Call(context.Background(), lanes...)
+		if err != nil {
+			return nil, err
+		}
+		if len(out) == 0 {
+			return sql.RowsToRowIter(), nil
+		}
+		if len(results) > 0 {
+			switch results[0] {
+			case api.ValueTypeF32:
+				return sql.RowsToRowIter(sql.Row{float64(api.DecodeF32(out[0]))}), nil
+			case api.ValueTypeF64:
+				return sql.RowsToRowIter(sql.Row{api.DecodeF64(out[0])}), nil
+			}
+		}
+		return sql.RowsToRowIter(sql.Row{int64(out[0])}), nil
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"reflect"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// CallArg pairs an external stored procedure call argument's runtime value with the sql.Type the caller's
+// expression evaluated it as, which is what CoercionRegistry.Coerce needs to find a registered rule when
+// Value's own Go representation doesn't already satisfy the target parameter.
+type CallArg struct {
+	Type  sql.Type
+	Value interface{}
+}
+
+// Call is the path a CALL executor should use to invoke a registered external stored procedure, instead of
+// looking esp.Function up and reflecting on it directly: it resolves the overload matching name and
+// len(args), coerces any argument that doesn't already satisfy its parameter's Go type via e.coercions, and
+// invokes the result through InvokeSecurely so SECURITY DEFINER/INVOKER and RequiredPrivileges are always
+// enforced.
+func (e ExternalStoredProcedureProvider) Call(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker, name string, args ...CallArg) ([]reflect.Value, error) {
+	esp, err := e.ExternalStoredProcedure(ctx, name, len(args))
+	if err != nil {
+		return nil, err
+	}
+
+	coercedArgs, err := e.coerceCallArgs(esp, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.InvokeSecurely(ctx, opChecker, esp, coercedArgs...)
+}
+
+// coerceCallArgs converts each of args into the Go type esp.Function's corresponding parameter declares,
+// via e.coercions, when Value isn't already assignable to it as-is. esp.Function's first parameter (the
+// *sql.Context) has no caller-supplied argument and is skipped.
+func (e ExternalStoredProcedureProvider) coerceCallArgs(esp *sql.ExternalStoredProcedureDetails, args []CallArg) ([]interface{}, error) {
+	fnType := reflect.TypeOf(esp.Function)
+	coerced := make([]interface{}, len(args))
+	for i, arg := range args {
+		paramType := fnParamType(fnType, i)
+		if arg.Value == nil || reflect.TypeOf(arg.Value).AssignableTo(paramType) {
+			coerced[i] = arg.Value
+			continue
+		}
+		v, _, err := e.coercions.Coerce(arg.Type, paramType, arg.Value)
+		if err != nil {
+			return nil, err
+		}
+		coerced[i] = v
+	}
+	return coerced, nil
+}
+
+// fnParamType returns the Go type fnType (an external stored procedure Function) declares for the
+// argIndex'th caller-supplied argument - the parameter one past fnType's leading *sql.Context - following
+// the last declared parameter's element type once argIndex reaches a variadic fnType's final slot.
+func fnParamType(fnType reflect.Type, argIndex int) reflect.Type {
+	paramIndex := argIndex + 1
+	if fnType.IsVariadic() && paramIndex >= fnType.NumIn()-1 {
+		return fnType.In(fnType.NumIn() - 1).Elem()
+	}
+	return fnType.In(paramIndex)
+}
+
+// InvokeSecurely enforces esp's RequiredPrivileges against opChecker, then
+// calls esp.Function with args, running the call under esp.Definer's
+// identity when esp.SecurityType is sql.ProcedureSecurityType_Definer and
+// restoring the caller's identity afterward - MySQL's SQL SECURITY DEFINER
+// vs INVOKER. A caller with a missing required privilege never reaches
+// Function at all.
+func (e ExternalStoredProcedureProvider) InvokeSecurely(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker, esp *sql.ExternalStoredProcedureDetails, args ...interface{}) ([]reflect.Value, error) {
+	for _, priv := range esp.RequiredPrivileges {
+		if !opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation(ctx.GetCurrentDatabase(), "", "", priv)) {
+			return nil, sql.ErrPrivilegeCheckFailed.New(ctx.Session.Client().User)
+		}
+	}
+
+	if esp.SecurityType == sql.ProcedureSecurityType_Definer && esp.Definer != "" {
+		restore := runAsDefiner(ctx, esp.Definer)
+		defer restore()
+	}
+
+	fn := reflect.ValueOf(esp.Function)
+	in := make([]reflect.Value, 0, len(args)+1)
+	in = append(in, reflect.ValueOf(ctx))
+	for _, a := range args {
+		in = append(in, reflect.ValueOf(a))
+	}
+	return fn.Call(in), nil
+}
+
+// runAsDefiner swaps ctx's session client to report definer as its user,
+// for the duration of a SECURITY DEFINER procedure call, and returns a
+// closure that restores the original client.
+func runAsDefiner(ctx *sql.Context, definer string) (restore func()) {
+	original := ctx.Session.Client()
+	asDefiner := original
+	asDefiner.User = definer
+	ctx.Session.SetClient(asDefiner)
+	return func() {
+		ctx.Session.SetClient(original)
+	}
+}
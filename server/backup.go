@@ -0,0 +1,341 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// BackupStorage is a pluggable sink/source for backup artifacts. Embedders
+// register implementations (local directory, S3, GCS, ...) on Config so
+// BACKUP DATABASE / RESTORE DATABASE can target arbitrary URIs without the
+// engine needing to know about any particular object store.
+type BackupStorage interface {
+	// Scheme returns the URI scheme this storage handles, e.g. "file", "s3".
+	Scheme() string
+	// Writer opens a stream to persist a single backup artifact named by
+	// uri, relative to the storage's root.
+	Writer(ctx context.Context, uri string) (io.WriteCloser, error)
+	// Reader opens a stream to read back a previously written artifact.
+	Reader(ctx context.Context, uri string) (io.ReadCloser, error)
+}
+
+// BackupEngine takes consistent snapshots of every registered sql.Database
+// on a Server and streams them to a BackupStorage, or restores a previously
+// taken snapshot into databases and tables that already exist in the
+// catalog; it does not create them. It is the server-level counterpart to
+// Engine in the same way sessionMgr is -- it operates across databases
+// rather than within a single session.
+type BackupEngine interface {
+	// Backup streams a logical snapshot of every database visible to ctx to
+	// the given destination URI and returns the totals in BackupStats
+	// (bytes written, database count, compression ratio). It records no
+	// per-phase progress counters, such as bytes read or fsync latency.
+	Backup(ctx *sql.Context, destURI string) (BackupStats, error)
+	// Restore reads a snapshot previously written by Backup from srcURI and
+	// replays it into the catalog visible to ctx.
+	Restore(ctx *sql.Context, srcURI string) error
+}
+
+// BackupStats reports the outcome of a single Backup call.
+type BackupStats struct {
+	// BytesWritten is the total size of the written artifact(s), after
+	// compression.
+	BytesWritten int64
+	// Databases is the number of databases included in the snapshot.
+	Databases int
+	// CompressionRatio is UncompressedBytes / BytesWritten, or 0 if unknown.
+	CompressionRatio float64
+}
+
+// NewBackupEngine constructs the default BackupEngine for a Server, using
+// the storage registered on cfg (Config.BackupStorage), or a local
+// directory rooted at cfg.BackupDir if none was registered.
+func NewBackupEngine(engine EngineProvider, cfg Config) BackupEngine {
+	storage := cfg.BackupStorage
+	if storage == nil {
+		storage = NewLocalDirBackupStorage(cfg.BackupDir)
+	}
+	return &backupEngine{engine: engine, storage: storage}
+}
+
+// EngineProvider is the subset of *gms.Engine that BackupEngine needs to
+// enumerate databases. It is expressed as an interface here so backup.go
+// does not import the root package and create an import cycle.
+type EngineProvider interface {
+	AllDatabases(ctx *sql.Context) []sql.Database
+}
+
+type backupEngine struct {
+	engine  EngineProvider
+	storage BackupStorage
+}
+
+// backupManifest is the gzipped-JSON artifact Backup writes and Restore reads back. It's deliberately a plain
+// logical dump (database/table/row triples) rather than a storage-engine-specific binary format, since
+// BackupStorage is generic over arbitrary integrators that have no shared on-disk row representation.
+type backupManifest struct {
+	Databases []backupDatabase `json:"databases"`
+}
+
+type backupDatabase struct {
+	Name   string        `json:"name"`
+	Tables []backupTable `json:"tables"`
+}
+
+type backupTable struct {
+	Name    string          `json:"name"`
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// Backup implements BackupEngine. It reads every table of every database visible to ctx in full, and writes
+// the result as gzip-compressed JSON to destURI.
+func (b *backupEngine) Backup(ctx *sql.Context, destURI string) (BackupStats, error) {
+	span, ctx := ctx.Span("server.Backup")
+	defer span.Finish()
+
+	dbs := b.engine.AllDatabases(ctx)
+	manifest := backupManifest{Databases: make([]backupDatabase, 0, len(dbs))}
+	for _, db := range dbs {
+		tableNames, err := db.GetTableNames(ctx)
+		if err != nil {
+			return BackupStats{}, err
+		}
+		bdb := backupDatabase{Name: db.Name(), Tables: make([]backupTable, 0, len(tableNames))}
+		for _, name := range tableNames {
+			table, ok, err := db.GetTableInsensitive(ctx, name)
+			if err != nil {
+				return BackupStats{}, err
+			}
+			if !ok {
+				continue
+			}
+			schema := table.Schema()
+			cols := make([]string, len(schema))
+			for i, c := range schema {
+				cols[i] = c.Name
+			}
+			var rows [][]interface{}
+			err = sql.IterRecordsFromPartitions(ctx, table, func(_ int64, row sql.Row) (bool, error) {
+				rows = append(rows, []interface{}(row))
+				return true, nil
+			})
+			if err != nil {
+				return BackupStats{}, err
+			}
+			bdb.Tables = append(bdb.Tables, backupTable{Name: name, Columns: cols, Rows: rows})
+		}
+		manifest.Databases = append(manifest.Databases, bdb)
+	}
+
+	uncompressed, err := json.Marshal(manifest)
+	if err != nil {
+		return BackupStats{}, err
+	}
+
+	w, err := b.storage.Writer(context.Background(), destURI)
+	if err != nil {
+		return BackupStats{}, err
+	}
+	defer w.Close()
+
+	counter := &countingWriter{w: w}
+	gz := gzip.NewWriter(counter)
+	if _, err := gz.Write(uncompressed); err != nil {
+		return BackupStats{}, err
+	}
+	if err := gz.Close(); err != nil {
+		return BackupStats{}, err
+	}
+
+	stats := BackupStats{BytesWritten: counter.n, Databases: len(dbs)}
+	if counter.n > 0 {
+		stats.CompressionRatio = float64(len(uncompressed)) / float64(counter.n)
+	}
+	return stats, nil
+}
+
+// Restore implements BackupEngine. It replays a manifest written by Backup, matching each database and table
+// by name (case-insensitively) against the catalog visible to ctx, and inserting rows through the target
+// table's ordinary sql.InsertableTable path - it does not create databases or tables that don't already
+// exist, since schema (re)creation belongs to whatever DDL produced the original tables.
+func (b *backupEngine) Restore(ctx *sql.Context, srcURI string) error {
+	span, ctx := ctx.Span("server.Restore")
+	defer span.Finish()
+
+	r, err := b.storage.Reader(context.Background(), srcURI)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	var manifest backupManifest
+	if err := json.NewDecoder(gz).Decode(&manifest); err != nil {
+		return err
+	}
+
+	dbsByName := make(map[string]sql.Database)
+	for _, db := range b.engine.AllDatabases(ctx) {
+		dbsByName[strings.ToLower(db.Name())] = db
+	}
+
+	for _, bdb := range manifest.Databases {
+		db, ok := dbsByName[strings.ToLower(bdb.Name)]
+		if !ok {
+			return fmt.Errorf("restore: database %q not found in catalog", bdb.Name)
+		}
+		for _, bt := range bdb.Tables {
+			if err := restoreTable(ctx, db, bt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreTable replays bt's rows into the table it names in db, converting each value back to the column's
+// declared sql.Type (JSON decoding otherwise leaves every number as a float64).
+func restoreTable(ctx *sql.Context, db sql.Database, bt backupTable) error {
+	table, ok, err := db.GetTableInsensitive(ctx, bt.Name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("restore: table %q not found in database %q", bt.Name, db.Name())
+	}
+	insertable, ok := table.(sql.InsertableTable)
+	if !ok {
+		return fmt.Errorf("restore: table %q does not support insertion", bt.Name)
+	}
+
+	schema := table.Schema()
+	inserter := insertable.Inserter(ctx)
+	for _, raw := range bt.Rows {
+		row, err := convertBackupRow(schema, bt.Columns, raw)
+		if err != nil {
+			inserter.Close(ctx)
+			return err
+		}
+		if err := inserter.Insert(ctx, row); err != nil {
+			inserter.Close(ctx)
+			return err
+		}
+	}
+	return inserter.Close(ctx)
+}
+
+// convertBackupRow maps raw, a JSON-decoded row recorded in column order cols, back onto schema's column
+// order, converting each value to its column's declared type. cols and schema are expected to already agree
+// on order (Backup writes them that way), but are matched by name regardless in case the target schema has
+// since been reordered.
+func convertBackupRow(schema sql.Schema, cols []string, raw []interface{}) (sql.Row, error) {
+	row := make(sql.Row, len(schema))
+	for i, colName := range cols {
+		if i >= len(raw) {
+			break
+		}
+		idx := i
+		if idx >= len(schema) || schema[idx].Name != colName {
+			idx = -1
+			for j, c := range schema {
+				if c.Name == colName {
+					idx = j
+					break
+				}
+			}
+		}
+		if idx < 0 {
+			continue
+		}
+		val := raw[i]
+		if val != nil {
+			converted, err := schema[idx].Type.Convert(val)
+			if err != nil {
+				return nil, err
+			}
+			val = converted
+		}
+		row[idx] = val
+	}
+	return row, nil
+}
+
+// countingWriter tracks how many bytes have been written through it, so Backup can report BytesWritten/
+// CompressionRatio without buffering the compressed artifact separately from the storage write itself.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// LocalDirBackupStorage is the default BackupStorage, writing artifacts as
+// plain files under a root directory on the local filesystem.
+type LocalDirBackupStorage struct {
+	root string
+}
+
+var _ BackupStorage = (*LocalDirBackupStorage)(nil)
+
+// NewLocalDirBackupStorage returns a BackupStorage rooted at dir.
+func NewLocalDirBackupStorage(dir string) *LocalDirBackupStorage {
+	return &LocalDirBackupStorage{root: dir}
+}
+
+// Scheme implements BackupStorage.
+func (l *LocalDirBackupStorage) Scheme() string {
+	return "file"
+}
+
+// Writer implements BackupStorage.
+func (l *LocalDirBackupStorage) Writer(ctx context.Context, uri string) (io.WriteCloser, error) {
+	return openLocalBackupFile(l.root, uri, true)
+}
+
+// Reader implements BackupStorage.
+func (l *LocalDirBackupStorage) Reader(ctx context.Context, uri string) (io.ReadCloser, error) {
+	return openLocalBackupFile(l.root, uri, false)
+}
+
+func openLocalBackupFile(root, uri string, write bool) (*os.File, error) {
+	path := filepath.Join(root, filepath.Clean("/"+uri))
+	if write {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		return os.Create(path)
+	}
+	return os.Open(path)
+}
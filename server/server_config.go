@@ -69,6 +69,14 @@ type Config struct {
 	// If true, queries will be logged as base64 encoded strings.
 	// If false (default behavior), queries will be logged as strings, but newlines and tabs will be replaced with spaces.
 	EncodeLoggedQuery bool
+	// BackupDir is the root directory used by the default local-disk
+	// BackupStorage when BackupStorage is not set.
+	BackupDir string
+	// BackupStorage is the storage backend BACKUP DATABASE / RESTORE
+	// DATABASE stream to. Embedders register a custom implementation (S3,
+	// GCS, ...) here; if nil, a LocalDirBackupStorage rooted at BackupDir
+	// is used instead.
+	BackupStorage BackupStorage
 }
 
 func (c Config) NewConfig() (Config, error) {
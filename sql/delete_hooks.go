@@ -0,0 +1,126 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// DeleteHook is a cross-cutting extension point plan.deleteIter invokes around every row it deletes, for
+// every target table of a DELETE statement - independent of whether the target table itself implements
+// HookableTable. Unlike HookableTable, which a single storage engine opts into for its own tables, a
+// DeleteHook registered once via Context.RegisterDeleteHook observes every delete run through that Context,
+// which is what change-data-capture, audit logging, and distributed-transaction participants need.
+type DeleteHook interface {
+	// BeforeDelete is called with the row about to be deleted from table, before the table's RowDeleter.Delete
+	// runs. Returning a non-nil error aborts the delete for this row: Delete is never called, and the error
+	// propagates up through deleteIter.Next the same as any other delete error.
+	BeforeDelete(ctx *Context, table string, row Row) error
+	// AfterDelete is called once the delete attempt for table, row has completed, whether or not it succeeded.
+	// err is nil on success, or the error that aborted it (from a BeforeDelete hook or from Delete itself).
+	AfterDelete(ctx *Context, table string, row Row, err error)
+}
+
+// deleteHookReg maps a Context to the DeleteHooks registered on it, keyed by pointer identity so hooks
+// registered through one Context (and so one engine/session) never fire for a delete run through another.
+var (
+	deleteHooksMu sync.RWMutex
+	deleteHookReg = map[*Context][]DeleteHook{}
+)
+
+// RegisterDeleteHook adds hook to the set ctx's own deletes consult - plan.deleteIter only ever sees the
+// hooks registered on the same Context it's running under. Hooks run in registration order for
+// BeforeDelete, and the same order for AfterDelete. The first call for a given ctx also starts a goroutine
+// that evicts ctx's entry once ctx.Done() fires, so a short-lived per-query Context doesn't pin its map
+// entry (and everything it references) for the life of the process.
+func (ctx *Context) RegisterDeleteHook(hook DeleteHook) {
+	deleteHooksMu.Lock()
+	_, alreadyRegistered := deleteHookReg[ctx]
+	deleteHookReg[ctx] = append(deleteHookReg[ctx], hook)
+	deleteHooksMu.Unlock()
+
+	if !alreadyRegistered {
+		go func() {
+			<-ctx.Done()
+			deleteHooksMu.Lock()
+			delete(deleteHookReg, ctx)
+			deleteHooksMu.Unlock()
+		}()
+	}
+}
+
+// DeleteHooks returns the delete hooks registered on ctx, in registration order.
+func (ctx *Context) DeleteHooks() []DeleteHook {
+	deleteHooksMu.RLock()
+	defer deleteHooksMu.RUnlock()
+	registered := deleteHookReg[ctx]
+	hooks := make([]DeleteHook, len(registered))
+	copy(hooks, registered)
+	return hooks
+}
+
+// deleteEvent is the JSON-lines record JSONDeleteHook writes for each row.
+type deleteEvent struct {
+	Table     string        `json:"table"`
+	PK        []interface{} `json:"pk"`
+	Timestamp time.Time     `json:"timestamp"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// JSONDeleteHook is a built-in DeleteHook that streams one JSON line per deleted row to sink, for audit or
+// CDC consumers tailing the output. Its "pk" field is the row's full column values rather than an isolated
+// primary key - deleteIter only ever hands a hook the row range belonging to one target table, with no
+// further schema information to single out which of those columns form its primary key.
+type JSONDeleteHook struct {
+	mu   sync.Mutex
+	sink io.Writer
+	now  func() time.Time
+}
+
+var _ DeleteHook = (*JSONDeleteHook)(nil)
+
+// NewJSONDeleteHook creates a JSONDeleteHook writing to sink.
+func NewJSONDeleteHook(sink io.Writer) *JSONDeleteHook {
+	return &JSONDeleteHook{sink: sink, now: time.Now}
+}
+
+// BeforeDelete implements the DeleteHook interface. JSONDeleteHook never aborts a delete.
+func (h *JSONDeleteHook) BeforeDelete(ctx *Context, table string, row Row) error {
+	return nil
+}
+
+// AfterDelete implements the DeleteHook interface, writing one JSON line recording the deletion.
+func (h *JSONDeleteHook) AfterDelete(ctx *Context, table string, row Row, err error) {
+	event := deleteEvent{
+		Table:     table,
+		PK:        []interface{}(row),
+		Timestamp: h.now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	encoded, marshalErr := json.Marshal(event)
+	if marshalErr != nil {
+		return
+	}
+	encoded = append(encoded, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, _ = h.sink.Write(encoded)
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import "github.com/dolthub/go-mysql-server/sql/types"
+
+// DeleteBatchSizeSessionVar tunes how many rows plan.deleteIter buffers for a sql.BatchRowDeleter before
+// flushing them in a single DeleteBatch call, for a target whose table implements it. A larger batch trades
+// memory for fewer round trips to the storage engine; a size of 1 behaves like the unbatched per-row
+// RowDeleter.Delete path.
+const DeleteBatchSizeSessionVar = "delete_batch_size"
+
+// DefaultDeleteBatchSize is the out-of-the-box buffer size for batched deletes.
+const DefaultDeleteBatchSize = 1024
+
+func init() {
+	SystemVariables.AddSystemVariables([]SystemVariable{
+		{
+			Name:              DeleteBatchSizeSessionVar,
+			Scope:             GetMysqlScope(SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(DeleteBatchSizeSessionVar, 1, 1_000_000, false),
+			Default:           int64(DefaultDeleteBatchSize),
+		},
+	})
+}
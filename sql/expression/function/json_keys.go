@@ -0,0 +1,119 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// JSONKeys implements the JSON_KEYS function.
+// https://dev.mysql.com/doc/refman/8.0/en/json-attribute-functions.html#function_json-keys
+type JSONKeys struct {
+	doc  sql.Expression
+	path sql.Expression // nil means the default path "$"
+}
+
+var _ sql.FunctionExpression = (*JSONKeys)(nil)
+
+// NewJSONKeys creates a new JSONKeys expression.
+func NewJSONKeys(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("json_keys", "1 or 2", len(args))
+	}
+	j := &JSONKeys{doc: args[0]}
+	if len(args) == 2 {
+		j.path = args[1]
+	}
+	return j, nil
+}
+
+// FunctionName implements sql.FunctionExpression.
+func (j *JSONKeys) FunctionName() string { return "json_keys" }
+
+// Type implements the sql.Expression interface.
+func (j *JSONKeys) Type() sql.Type { return types.JSON }
+
+// IsNullable implements the sql.Expression interface.
+func (j *JSONKeys) IsNullable() bool { return true }
+
+// Children implements the sql.Expression interface.
+func (j *JSONKeys) Children() []sql.Expression {
+	if j.path == nil {
+		return []sql.Expression{j.doc}
+	}
+	return []sql.Expression{j.doc, j.path}
+}
+
+// Resolved implements the sql.Expression interface.
+func (j *JSONKeys) Resolved() bool {
+	if j.doc != nil && !j.doc.Resolved() {
+		return false
+	}
+	return j.path == nil || j.path.Resolved()
+}
+
+// WithChildren implements the sql.Expression interface.
+func (j *JSONKeys) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewJSONKeys(children...)
+}
+
+func (j *JSONKeys) String() string {
+	if j.path == nil {
+		return fmt.Sprintf("json_keys(%s)", j.doc.String())
+	}
+	return fmt.Sprintf("json_keys(%s, %s)", j.doc.String(), j.path.String())
+}
+
+// Eval implements the sql.Expression interface.
+func (j *JSONKeys) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	docVal, err := j.doc.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if docVal == nil {
+		return nil, nil
+	}
+
+	path := "$"
+	if j.path != nil {
+		pathVal, err := j.path.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if pathVal == nil {
+			return nil, nil
+		}
+		path = strings.TrimSpace(fmt.Sprint(pathVal))
+	}
+
+	converted, err := types.JSON.Convert(docVal)
+	if err != nil {
+		return nil, err
+	}
+	searchable, ok := converted.(types.SearchableJSONValue)
+	if !ok {
+		unmarshalled, err := converted.(types.JSONValue).Unmarshall(ctx)
+		if err != nil {
+			return nil, err
+		}
+		searchable = unmarshalled
+	}
+
+	return searchable.Keys(ctx, path)
+}
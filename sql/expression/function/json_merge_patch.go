@@ -0,0 +1,113 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// JSONMergePatch implements the JSON_MERGE_PATCH function.
+// https://dev.mysql.com/doc/refman/8.0/en/json-modification-functions.html#function_json-merge-patch
+type JSONMergePatch struct {
+	args []sql.Expression
+}
+
+var _ sql.FunctionExpression = (*JSONMergePatch)(nil)
+
+// NewJSONMergePatch creates a new JSONMergePatch expression.
+func NewJSONMergePatch(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) < 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("json_merge_patch", "2 or more", len(args))
+	}
+	return &JSONMergePatch{args: args}, nil
+}
+
+// FunctionName implements sql.FunctionExpression.
+func (j *JSONMergePatch) FunctionName() string { return "json_merge_patch" }
+
+// Type implements the sql.Expression interface.
+func (j *JSONMergePatch) Type() sql.Type { return types.JSON }
+
+// IsNullable implements the sql.Expression interface.
+func (j *JSONMergePatch) IsNullable() bool { return true }
+
+// Children implements the sql.Expression interface.
+func (j *JSONMergePatch) Children() []sql.Expression { return j.args }
+
+// Resolved implements the sql.Expression interface.
+func (j *JSONMergePatch) Resolved() bool {
+	for _, arg := range j.args {
+		if !arg.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// WithChildren implements the sql.Expression interface.
+func (j *JSONMergePatch) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(j.args) {
+		return nil, sql.ErrInvalidChildrenNumber.New(j, len(children), len(j.args))
+	}
+	return NewJSONMergePatch(children...)
+}
+
+func (j *JSONMergePatch) String() string {
+	var args []string
+	for _, e := range j.args {
+		args = append(args, e.String())
+	}
+	return fmt.Sprintf("json_merge_patch(%s)", strings.Join(args, ", "))
+}
+
+// Eval implements the sql.Expression interface. If any document is SQL
+// NULL, JSON_MERGE_PATCH returns NULL, matching MySQL.
+func (j *JSONMergePatch) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	var result types.MutableJSONValue
+	for i, arg := range j.args {
+		val, err := arg.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, nil
+		}
+		doc, err := types.JSON.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+		jv := doc.(types.JSONValue)
+
+		if i == 0 {
+			unmarshalled, err := jv.Unmarshall(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result = unmarshalled
+			continue
+		}
+
+		merged, _, err := result.MergePatch(ctx, jv)
+		if err != nil {
+			return nil, err
+		}
+		result = merged
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// JSONOverlaps implements the JSON_OVERLAPS function.
+// https://dev.mysql.com/doc/refman/8.0/en/json-search-functions.html#function_json-overlaps
+type JSONOverlaps struct {
+	a sql.Expression
+	b sql.Expression
+}
+
+var _ sql.FunctionExpression = (*JSONOverlaps)(nil)
+
+// NewJSONOverlaps creates a new JSONOverlaps expression.
+func NewJSONOverlaps(a, b sql.Expression) sql.Expression {
+	return &JSONOverlaps{a: a, b: b}
+}
+
+// FunctionName implements sql.FunctionExpression.
+func (j *JSONOverlaps) FunctionName() string { return "json_overlaps" }
+
+// Type implements the sql.Expression interface.
+func (j *JSONOverlaps) Type() sql.Type { return types.Boolean }
+
+// IsNullable implements the sql.Expression interface.
+func (j *JSONOverlaps) IsNullable() bool { return true }
+
+// Children implements the sql.Expression interface.
+func (j *JSONOverlaps) Children() []sql.Expression { return []sql.Expression{j.a, j.b} }
+
+// Resolved implements the sql.Expression interface.
+func (j *JSONOverlaps) Resolved() bool { return j.a.Resolved() && j.b.Resolved() }
+
+// WithChildren implements the sql.Expression interface.
+func (j *JSONOverlaps) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(j, len(children), 2)
+	}
+	return NewJSONOverlaps(children[0], children[1]), nil
+}
+
+func (j *JSONOverlaps) String() string {
+	return fmt.Sprintf("json_overlaps(%s, %s)", j.a.String(), j.b.String())
+}
+
+// Eval implements the sql.Expression interface.
+func (j *JSONOverlaps) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	aVal, err := j.a.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	bVal, err := j.b.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if aVal == nil || bVal == nil {
+		return nil, nil
+	}
+
+	aConverted, err := types.JSON.Convert(aVal)
+	if err != nil {
+		return nil, err
+	}
+	bConverted, err := types.JSON.Convert(bVal)
+	if err != nil {
+		return nil, err
+	}
+
+	aSearchable, err := asSearchableJSONValue(ctx, aConverted)
+	if err != nil {
+		return nil, err
+	}
+	bSearchable, err := asSearchableJSONValue(ctx, bConverted)
+	if err != nil {
+		return nil, err
+	}
+
+	return aSearchable.Overlaps(ctx, bSearchable)
+}
+
+// asSearchableJSONValue returns val as a types.SearchableJSONValue, falling back to a full Unmarshall for a
+// LazyJSONValue implementer that isn't one - see types.SearchableJSONValue's doc comment.
+func asSearchableJSONValue(ctx *sql.Context, val interface{}) (types.SearchableJSONValue, error) {
+	if searchable, ok := val.(types.SearchableJSONValue); ok {
+		return searchable, nil
+	}
+	return val.(types.JSONValue).Unmarshall(ctx)
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// JSONPatch implements the JSON_PATCH function, applying an RFC 6902 JSON
+// Patch document to a JSON value.
+type JSONPatch struct {
+	doc   sql.Expression
+	patch sql.Expression
+}
+
+var _ sql.FunctionExpression = (*JSONPatch)(nil)
+
+// NewJSONPatch creates a new JSONPatch expression.
+func NewJSONPatch(doc, patch sql.Expression) sql.Expression {
+	return &JSONPatch{doc: doc, patch: patch}
+}
+
+// FunctionName implements sql.FunctionExpression.
+func (j *JSONPatch) FunctionName() string { return "json_patch" }
+
+// Type implements the sql.Expression interface.
+func (j *JSONPatch) Type() sql.Type { return types.JSON }
+
+// IsNullable implements the sql.Expression interface.
+func (j *JSONPatch) IsNullable() bool { return true }
+
+// Children implements the sql.Expression interface.
+func (j *JSONPatch) Children() []sql.Expression { return []sql.Expression{j.doc, j.patch} }
+
+// Resolved implements the sql.Expression interface.
+func (j *JSONPatch) Resolved() bool { return j.doc.Resolved() && j.patch.Resolved() }
+
+// WithChildren implements the sql.Expression interface.
+func (j *JSONPatch) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(j, len(children), 2)
+	}
+	return NewJSONPatch(children[0], children[1]), nil
+}
+
+func (j *JSONPatch) String() string {
+	return fmt.Sprintf("json_patch(%s, %s)", j.doc.String(), j.patch.String())
+}
+
+// Eval implements the sql.Expression interface.
+func (j *JSONPatch) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	docVal, err := j.doc.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if docVal == nil {
+		return nil, nil
+	}
+	patchVal, err := j.patch.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if patchVal == nil {
+		return nil, nil
+	}
+
+	docConverted, err := types.JSON.Convert(docVal)
+	if err != nil {
+		return nil, err
+	}
+	patchConverted, err := types.JSON.Convert(patchVal)
+	if err != nil {
+		return nil, err
+	}
+
+	mutable := docConverted.(types.MutableJSONValue)
+	result, _, err := mutable.ApplyPatch(ctx, patchConverted.(types.JSONValue))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// JSONRemove implements the JSON_REMOVE function.
+// https://dev.mysql.com/doc/refman/8.0/en/json-modification-functions.html#function_json-remove
+type JSONRemove struct {
+	doc   sql.Expression
+	paths []sql.Expression
+}
+
+var _ sql.FunctionExpression = (*JSONRemove)(nil)
+
+// NewJSONRemove creates a new JSONRemove expression.
+func NewJSONRemove(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) < 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("json_remove", "2 or more", len(args))
+	}
+	return &JSONRemove{doc: args[0], paths: args[1:]}, nil
+}
+
+// FunctionName implements sql.FunctionExpression.
+func (j *JSONRemove) FunctionName() string { return "json_remove" }
+
+// Type implements the sql.Expression interface.
+func (j *JSONRemove) Type() sql.Type { return types.JSON }
+
+// IsNullable implements the sql.Expression interface.
+func (j *JSONRemove) IsNullable() bool { return true }
+
+// Children implements the sql.Expression interface.
+func (j *JSONRemove) Children() []sql.Expression {
+	return append([]sql.Expression{j.doc}, j.paths...)
+}
+
+// Resolved implements the sql.Expression interface.
+func (j *JSONRemove) Resolved() bool {
+	if !j.doc.Resolved() {
+		return false
+	}
+	for _, p := range j.paths {
+		if !p.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// WithChildren implements the sql.Expression interface.
+func (j *JSONRemove) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewJSONRemove(children...)
+}
+
+func (j *JSONRemove) String() string {
+	var args []string
+	args = append(args, j.doc.String())
+	for _, p := range j.paths {
+		args = append(args, p.String())
+	}
+	return fmt.Sprintf("json_remove(%s)", strings.Join(args, ", "))
+}
+
+// Eval implements the sql.Expression interface. Paths are applied in the
+// order given, matching MySQL, so removing an array element can shift the
+// indexes a later path in the same call refers to.
+func (j *JSONRemove) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	docVal, err := j.doc.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if docVal == nil {
+		return nil, nil
+	}
+
+	converted, err := types.JSON.Convert(docVal)
+	if err != nil {
+		return nil, err
+	}
+	mutable := converted.(types.MutableJSONValue)
+
+	for _, pathExpr := range j.paths {
+		pathVal, err := pathExpr.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if pathVal == nil {
+			return nil, nil
+		}
+		mutable, _, err = mutable.Remove(ctx, fmt.Sprint(pathVal))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mutable, nil
+}
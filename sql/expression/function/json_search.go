@@ -0,0 +1,118 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// JSONSearch implements the JSON_SEARCH function.
+// https://dev.mysql.com/doc/refman/8.0/en/json-search-functions.html#function_json-search
+type JSONSearch struct {
+	// args is doc, one_or_all, search_str, [escape_char, [path]...]
+	args []sql.Expression
+}
+
+var _ sql.FunctionExpression = (*JSONSearch)(nil)
+
+// NewJSONSearch creates a new JSONSearch expression.
+func NewJSONSearch(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) < 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New("json_search", "3 or more", len(args))
+	}
+	return &JSONSearch{args: args}, nil
+}
+
+// FunctionName implements sql.FunctionExpression.
+func (j *JSONSearch) FunctionName() string { return "json_search" }
+
+// Type implements the sql.Expression interface.
+func (j *JSONSearch) Type() sql.Type { return types.JSON }
+
+// IsNullable implements the sql.Expression interface.
+func (j *JSONSearch) IsNullable() bool { return true }
+
+// Children implements the sql.Expression interface.
+func (j *JSONSearch) Children() []sql.Expression { return j.args }
+
+// Resolved implements the sql.Expression interface.
+func (j *JSONSearch) Resolved() bool {
+	for _, arg := range j.args {
+		if !arg.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// WithChildren implements the sql.Expression interface.
+func (j *JSONSearch) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewJSONSearch(children...)
+}
+
+func (j *JSONSearch) String() string {
+	var args []string
+	for _, e := range j.args {
+		args = append(args, e.String())
+	}
+	return fmt.Sprintf("json_search(%s)", strings.Join(args, ", "))
+}
+
+// Eval implements the sql.Expression interface.
+func (j *JSONSearch) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	vals := make([]interface{}, len(j.args))
+	for i, arg := range j.args {
+		val, err := arg.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, nil
+		}
+		vals[i] = val
+	}
+
+	converted, err := types.JSON.Convert(vals[0])
+	if err != nil {
+		return nil, err
+	}
+	searchable, ok := converted.(types.SearchableJSONValue)
+	if !ok {
+		unmarshalled, err := converted.(types.JSONValue).Unmarshall(ctx)
+		if err != nil {
+			return nil, err
+		}
+		searchable = unmarshalled
+	}
+
+	oneOrAll := fmt.Sprint(vals[1])
+	searchStr := fmt.Sprint(vals[2])
+
+	escape := ""
+	if len(vals) > 3 {
+		escape = fmt.Sprint(vals[3])
+	}
+
+	var paths []string
+	for i := 4; i < len(vals); i++ {
+		paths = append(paths, fmt.Sprint(vals[i]))
+	}
+
+	return searchable.Search(ctx, oneOrAll, searchStr, escape, paths...)
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// JSONType implements the JSON_TYPE function.
+// https://dev.mysql.com/doc/refman/8.0/en/json-attribute-functions.html#function_json-type
+type JSONType struct {
+	doc sql.Expression
+}
+
+var _ sql.FunctionExpression = (*JSONType)(nil)
+
+// NewJSONType creates a new JSONType expression.
+func NewJSONType(doc sql.Expression) sql.Expression {
+	return &JSONType{doc: doc}
+}
+
+// FunctionName implements sql.FunctionExpression.
+func (j *JSONType) FunctionName() string { return "json_type" }
+
+// Type implements the sql.Expression interface.
+func (j *JSONType) Type() sql.Type { return types.LongText }
+
+// IsNullable implements the sql.Expression interface.
+func (j *JSONType) IsNullable() bool { return true }
+
+// Children implements the sql.Expression interface.
+func (j *JSONType) Children() []sql.Expression { return []sql.Expression{j.doc} }
+
+// Resolved implements the sql.Expression interface.
+func (j *JSONType) Resolved() bool { return j.doc.Resolved() }
+
+// WithChildren implements the sql.Expression interface.
+func (j *JSONType) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(j, len(children), 1)
+	}
+	return NewJSONType(children[0]), nil
+}
+
+func (j *JSONType) String() string {
+	return fmt.Sprintf("json_type(%s)", j.doc.String())
+}
+
+// Eval implements the sql.Expression interface.
+func (j *JSONType) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := j.doc.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, nil
+	}
+
+	converted, err := types.JSON.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+	jv := converted.(types.JSONValue)
+
+	cat, err := jv.TypeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cat.String(), nil
+}
@@ -208,50 +208,101 @@ func (r *RegexpReplace) Eval(ctx *sql.Context, row sql.Row) (interface{}, error)
 	}
 	_occ := int(occ.(int32))
 
+	// MySQL's replacement string uses \1-\9 for numbered backreferences, \0 for the whole match, and \\ for a
+	// literal backslash - translate that into Go's $1/${1} convention (and escape any literal $ from the
+	// user) before handing it to the regexp package, which only understands its own syntax.
+	translatedReplaceStr := translateMySQLReplacement(_replaceStr)
+
 	// MySQL interprets negative occurrences as first for some reason
 	if _occ < 0 {
 		_occ = 1
 	} else if _occ == 0 {
 		// Replace everything
-		return _str[:_pos-1] + r.re.ReplaceAllString(_str[_pos-1:], _replaceStr), nil
+		return _str[:_pos-1] + r.re.ReplaceAllString(_str[_pos-1:], translatedReplaceStr), nil
 	}
 
 	// Split string into prefix and suffix
 	prefix := _str[:_pos-1]
 	suffix := _str[_pos-1:]
 
-	// Extract all matches
-	matches := r.re.FindAllString(suffix, -1)
-	indexes := r.re.FindAllStringIndex(suffix, -1)
+	// Extract the index of every match and its submatches, so the nth occurrence's replacement can resolve
+	// backreferences against that specific match, the same as MySQL does.
+	submatches := r.re.FindAllStringSubmatchIndex(suffix, -1)
 
 	// No matches, return original string
-	if len(matches) == 0 {
+	if len(submatches) == 0 {
 		return _str, nil
 	}
 
 	// If there aren't enough occurrences
-	if _occ > len(matches) {
+	if _occ > len(submatches) {
 		return _str, nil
 	}
 
-	// Replace only the nth occurrence
-	matches[_occ-1] = _replaceStr
+	// Replace only the nth occurrence, expanding backreferences against that match's submatches; every other
+	// occurrence is left as its original matched text.
+	matches := make([]string, len(submatches))
+	for i, sm := range submatches {
+		if i == _occ-1 {
+			matches[i] = string(r.re.ExpandString(nil, translatedReplaceStr, suffix, sm))
+		} else {
+			matches[i] = suffix[sm[0]:sm[1]]
+		}
+	}
 
 	// Initialize result string
-	res := prefix                 // attach prefix
-	res += suffix[:indexes[0][0]] // attach text before first match
-	res += matches[0]             // attach first match
+	res := prefix                    // attach prefix
+	res += suffix[:submatches[0][0]] // attach text before first match
+	res += matches[0]                // attach first match
 
 	// Recombine rest of matches
 	for i := 1; i < len(matches); i++ {
 		// Attach text before match
-		res += suffix[indexes[i-1][1]:indexes[i][0]] // end of prev to start of curr match
+		res += suffix[submatches[i-1][1]:submatches[i][0]] // end of prev to start of curr match
 		// Attach match
 		res += matches[i]
 	}
 
 	// Append text after last match
-	res += suffix[indexes[len(indexes)-1][1]:]
+	res += suffix[submatches[len(submatches)-1][1]:]
 
 	return res, nil
 }
+
+// translateMySQLReplacement converts a MySQL REGEXP_REPLACE replacement string into the syntax Go's
+// regexp.Expand family understands: \0-\9 become ${0}-${9} (MySQL's whole-match and numbered backreferences),
+// \\ becomes a literal backslash, and a literal $ is escaped to $$ so it isn't mistaken for the start of a Go
+// backreference. Any other backslash escape is passed through unchanged, the same as MySQL does for an escape
+// it doesn't recognize.
+func translateMySQLReplacement(replaceStr string) string {
+	var b strings.Builder
+	for i := 0; i < len(replaceStr); i++ {
+		c := replaceStr[i]
+		if c == '$' {
+			b.WriteString("$$")
+			continue
+		}
+		if c == '\\' && i+1 < len(replaceStr) {
+			next := replaceStr[i+1]
+			switch {
+			case next >= '0' && next <= '9':
+				b.WriteString("${")
+				b.WriteByte(next)
+				b.WriteByte('}')
+				i++
+				continue
+			case next == '\\':
+				b.WriteByte('\\')
+				i++
+				continue
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(next)
+				i++
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
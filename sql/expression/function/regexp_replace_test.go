@@ -0,0 +1,97 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+// evalRegexpReplace builds and evaluates a REGEXP_REPLACE(str, pattern, replace[, pos[, occurrence[, match_type]]])
+// call, in the same argument order the function accepts, over an empty row.
+func evalRegexpReplace(t *testing.T, args ...interface{}) (interface{}, error) {
+	t.Helper()
+
+	exprs := make([]sql.Expression, len(args))
+	for i, a := range args {
+		switch v := a.(type) {
+		case string:
+			exprs[i] = expression.NewLiteral(v, sql.LongText)
+		case int32:
+			exprs[i] = expression.NewLiteral(v, sql.Int32)
+		default:
+			t.Fatalf("unsupported arg type %T", a)
+		}
+	}
+
+	f, err := NewRegexpReplace(exprs...)
+	if err != nil {
+		t.Fatalf("NewRegexpReplace: %v", err)
+	}
+	return f.Eval(sql.NewEmptyContext(), sql.Row{})
+}
+
+func TestRegexpReplaceBackreferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []interface{}
+		expected string
+	}{
+		{
+			name:     "whole match backreference \\0, replace all",
+			args:     []interface{}{"hello world", "o", `[\0]`},
+			expected: "hell[o] w[o]rld",
+		},
+		{
+			name:     "numbered backreferences reorder captured groups",
+			args:     []interface{}{"abcd", `(\w)(\w)`, `\2\1`},
+			expected: "badc",
+		},
+		{
+			name:     "literal $ in the replacement is passed through, not treated as a Go backreference",
+			args:     []interface{}{"price", "price", "$100"},
+			expected: "$100",
+		},
+		{
+			name:     "escaped backslash produces one literal backslash",
+			args:     []interface{}{"x", "x", `\\`},
+			expected: `\`,
+		},
+		{
+			name:     "backreference resolves against only the requested nth occurrence",
+			args:     []interface{}{"aaa", "a", `[\0]`, int32(1), int32(2)},
+			expected: "a[a]a",
+		},
+		{
+			name:     "match_type 'i' makes the pattern case-insensitive",
+			args:     []interface{}{"Hello World", "O", "0", int32(1), int32(0), "i"},
+			expected: "Hell0 W0rld",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evalRegexpReplace(t, tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+// ProcedureSecurityType selects whose privileges an external stored procedure call is checked and run
+// under, mirroring MySQL's SQL SECURITY DEFINER vs INVOKER clause.
+type ProcedureSecurityType uint8
+
+const (
+	// ProcedureSecurityType_Invoker runs and checks privileges as the calling user - the default.
+	ProcedureSecurityType_Invoker ProcedureSecurityType = iota
+	// ProcedureSecurityType_Definer runs as Definer once RequiredPrivileges have cleared for the caller.
+	ProcedureSecurityType_Definer
+)
+
+// ExternalStoredProcedureDetails describes a stored procedure implemented in Go (or loaded from a plugin or
+// WASM module) rather than defined in SQL.
+type ExternalStoredProcedureDetails struct {
+	// Name is the name used to invoke the procedure.
+	Name string
+	// Schema is the procedure's result schema, or nil if it can only be determined once its arguments are
+	// known - see ExternalStoredProcedureProvider implementations for how a nil Schema is handled.
+	Schema Schema
+	// Function is the Go function implementing the procedure's logic.
+	Function interface{}
+	// Definer is the user whose identity the call runs under when SecurityType is
+	// ProcedureSecurityType_Definer. Ignored otherwise.
+	Definer string
+	// SecurityType selects whose identity Function runs under; the zero value, ProcedureSecurityType_Invoker,
+	// runs as the calling user.
+	SecurityType ProcedureSecurityType
+	// RequiredPrivileges must all be held by the calling user for the call to proceed, checked before
+	// Function runs regardless of SecurityType.
+	RequiredPrivileges []PrivilegeType
+}
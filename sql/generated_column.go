@@ -0,0 +1,82 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	errors "gopkg.in/src-d/go-errors.v1"
+)
+
+// GeneratedColumnType distinguishes the two kinds of `GENERATED ALWAYS AS (...)` column MySQL supports.
+// A Virtual column's expression is re-evaluated every time the row is read and is never written to disk; a
+// Stored column's expression is evaluated once on INSERT/UPDATE and the result is persisted alongside the
+// rest of the row, the same as an ordinary column.
+type GeneratedColumnType byte
+
+const (
+	GeneratedColumnType_Virtual GeneratedColumnType = iota
+	GeneratedColumnType_Stored
+)
+
+// GeneratedColumnDetails describes a single generated column: the expression that computes its value, and
+// whether that value is recomputed on every read (Virtual) or materialized once on write (Stored).
+type GeneratedColumnDetails struct {
+	Expression Expression
+	Type       GeneratedColumnType
+}
+
+// ErrGeneratedColumnNonDeterministic is returned when a STORED generated column's expression is not
+// deterministic. Unlike a Virtual column, a Stored column's value is persisted once and must stay
+// consistent on every future read, so MySQL rejects a non-deterministic expression (e.g. one referencing
+// RAND() or NOW()) at the point the column is declared.
+var ErrGeneratedColumnNonDeterministic = errors.NewKind("generated column '%s' expression is not deterministic")
+
+// ErrGeneratedColumnValueSupplied is returned when an INSERT or UPDATE statement supplies an explicit value
+// for a generated column. MySQL computes both Virtual and Stored generated column values itself; a caller
+// may only omit the column or specify DEFAULT for it.
+var ErrGeneratedColumnValueSupplied = errors.NewKind("the value specified for generated column '%s' in table '%s' is not allowed")
+
+// NonDeterministicExpression is implemented by expressions, such as RAND() and UUID(), whose result can
+// differ between two evaluations given the same row. CheckGeneratedColumnDeterministic uses it to reject
+// STORED generated column expressions that MySQL would refuse to persist.
+type NonDeterministicExpression interface {
+	Expression
+	IsNonDeterministic() bool
+}
+
+// CheckGeneratedColumnDeterministic returns an error if expr is not safe to use as a STORED generated
+// column's expression. STORED generated columns persist their computed value, so a non-deterministic
+// expression would silently drift from what re-evaluating it today would produce - the same hazard a
+// replica recomputing a non-deterministic DEFAULT would hit.
+func CheckGeneratedColumnDeterministic(columnName string, expr Expression) error {
+	if nonDeterministicSubExpr(expr) {
+		return ErrGeneratedColumnNonDeterministic.New(columnName)
+	}
+	return nil
+}
+
+func nonDeterministicSubExpr(expr Expression) bool {
+	if expr == nil {
+		return false
+	}
+	if nd, ok := expr.(NonDeterministicExpression); ok && nd.IsNonDeterministic() {
+		return true
+	}
+	for _, child := range expr.Children() {
+		if nonDeterministicSubExpr(child) {
+			return true
+		}
+	}
+	return false
+}
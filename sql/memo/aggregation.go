@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Aggregation is the logical RelExpr for GROUP BY / aggregate function
+// evaluation. Unlike most other logical rels, Aggregation has two physical
+// alternatives -- HashAgg and StreamAgg -- memoized into the same group so
+// the coster can pick between them the same way it picks a join algorithm.
+type Aggregation struct {
+	*relBase
+	Child        *ExprGroup
+	GroupBy      []sql.Expression
+	Aggregations []sql.Expression
+	// Physical indicates which alternative this particular list node
+	// represents; AggPhysicalUnknown marks the not-yet-costed logical form.
+	Physical AggPhysical
+}
+
+var _ RelExpr = (*Aggregation)(nil)
+
+func (r *Aggregation) Children() []*ExprGroup {
+	return []*ExprGroup{r.Child}
+}
+
+func (r *Aggregation) String() string {
+	return FormatExpr(r)
+}
+
+// AggPhysical enumerates the physical implementations of an Aggregation.
+type AggPhysical uint8
+
+const (
+	AggPhysicalUnknown AggPhysical = iota
+	// AggPhysicalHash builds an in-memory hash table keyed by GroupBy,
+	// accumulating aggregate state per key. Works for any input order but
+	// uses O(distinct groups) memory.
+	AggPhysicalHash
+	// AggPhysicalStream assumes its child is sorted on GroupBy and
+	// accumulates aggregate state for one group at a time, emitting a row
+	// as soon as the group key changes. O(1) memory, but requires a sort
+	// (or an index) on GroupBy underneath.
+	AggPhysicalStream
+)
+
+// HintTypeHashAgg and HintTypeStreamAgg force Memo.ApplyHint to prefer the named Aggregation
+// physical alternative (AggPhysicalHash / AggPhysicalStream respectively) over the coster's own
+// estimate, the same way the join-operator hints force a join algorithm.
+const (
+	HintTypeHashAgg HintType = iota + 100
+	HintTypeStreamAgg
+)
+
+// MemoizeAggregation memoizes both the hash and stream physical
+// alternatives for a GROUP BY, letting the coster choose based on whether
+// the child is already sorted on the grouping columns.
+func (m *Memo) MemoizeAggregation(grp, child *ExprGroup, groupBy, aggs []sql.Expression) *ExprGroup {
+	hash := &Aggregation{relBase: &relBase{}, Child: child, GroupBy: groupBy, Aggregations: aggs, Physical: AggPhysicalHash}
+	stream := &Aggregation{relBase: &relBase{}, Child: child, GroupBy: groupBy, Aggregations: aggs, Physical: AggPhysicalStream}
+
+	if grp == nil {
+		grp = m.NewExprGroup(hash)
+	} else {
+		hash.g = grp
+		grp.Prepend(hash)
+	}
+	stream.g = grp
+	grp.Prepend(stream)
+	return grp
+}
@@ -0,0 +1,221 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+// implication answers two narrow questions about a pair of filter
+// predicates over the same column: does one imply the other (so the
+// implied predicate is redundant and can be dropped), and are they
+// mutually exclusive (so their conjunction is always false, letting the
+// optimizer fold the whole subtree to a TableDual with zero rows)?
+//
+// Only conjunctions of simple comparisons against a single column are
+// handled (`x > 1 AND x > 0` => drop `x > 0`; `x > 1 AND x < 0` => always
+// false); anything more complex is left alone rather than risk an
+// incorrect pruning.
+type implication struct {
+	col *expression.GetField
+	op  cmpOp
+	val interface{}
+}
+
+type cmpOp uint8
+
+const (
+	cmpEq cmpOp = iota
+	cmpLt
+	cmpLeq
+	cmpGt
+	cmpGeq
+)
+
+// asImplication extracts the (column, operator, literal) triple from a
+// simple comparison expression, or ok=false if e isn't one of the
+// recognized shapes.
+func asImplication(e sql.Expression) (implication, bool) {
+	type binary interface {
+		Left() sql.Expression
+		Right() sql.Expression
+	}
+	b, ok := e.(binary)
+	if !ok {
+		return implication{}, false
+	}
+	gf, ok := b.Left().(*expression.GetField)
+	if !ok {
+		return implication{}, false
+	}
+	lit, ok := b.Right().(*expression.Literal)
+	if !ok {
+		return implication{}, false
+	}
+
+	var op cmpOp
+	switch e.(type) {
+	case *expression.Equals:
+		op = cmpEq
+	case *expression.LessThan:
+		op = cmpLt
+	case *expression.LessThanOrEqual:
+		op = cmpLeq
+	case *expression.GreaterThan:
+		op = cmpGt
+	case *expression.GreaterThanOrEqual:
+		op = cmpGeq
+	default:
+		return implication{}, false
+	}
+
+	return implication{col: gf, op: op, val: lit.Value()}, true
+}
+
+// pruneImplied removes filters from |filters| that are implied by another
+// filter in the same list over the same column, and reports whether the
+// remaining conjunction is provably unsatisfiable.
+func pruneImplied(filters []sql.Expression) (remaining []sql.Expression, alwaysFalse bool) {
+	kept := make([]bool, len(filters))
+	for i := range filters {
+		kept[i] = true
+	}
+
+	for i, fi := range filters {
+		ii, ok := asImplication(fi)
+		if !ok || !kept[i] {
+			continue
+		}
+		for j, fj := range filters {
+			if i == j || !kept[j] {
+				continue
+			}
+			ij, ok := asImplication(fj)
+			if !ok || ij.col.Name() != ii.col.Name() {
+				continue
+			}
+			if refutes(ii, ij) {
+				return nil, true
+			}
+			if implies(ij, ii) {
+				kept[i] = false
+				break
+			}
+		}
+	}
+
+	for i, f := range filters {
+		if kept[i] {
+			remaining = append(remaining, f)
+		}
+	}
+	return remaining, false
+}
+
+// implies reports whether satisfying |a| guarantees |b| is also satisfied,
+// for two comparisons against the same column and a comparable literal
+// value. Only numeric/string-ordered comparisons of the same Go type are
+// considered; anything else conservatively returns false.
+func implies(a, b implication) bool {
+	cmp, ok := compareLiterals(a.val, b.val)
+	if !ok {
+		return false
+	}
+	switch a.op {
+	case cmpGt, cmpGeq:
+		if b.op != cmpGt && b.op != cmpGeq {
+			return false
+		}
+		if a.op == cmpGeq && b.op == cmpGt {
+			return cmp > 0
+		}
+		return cmp >= 0
+	case cmpLt, cmpLeq:
+		if b.op != cmpLt && b.op != cmpLeq {
+			return false
+		}
+		if a.op == cmpLeq && b.op == cmpLt {
+			return cmp < 0
+		}
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// refutes reports whether |a| and |b| can never both be true.
+func refutes(a, b implication) bool {
+	cmp, ok := compareLiterals(a.val, b.val)
+	if !ok {
+		return false
+	}
+	lower, upper := a, b
+	if (a.op == cmpGt || a.op == cmpGeq) && (b.op == cmpLt || b.op == cmpLeq) {
+		lower, upper = a, b
+	} else if (b.op == cmpGt || b.op == cmpGeq) && (a.op == cmpLt || a.op == cmpLeq) {
+		lower, upper = b, a
+		cmp = -cmp
+	} else {
+		return false
+	}
+	if cmp < 0 {
+		// lower's bound is below upper's bound: e.g. x > 1 AND x < 5, fine.
+		return false
+	}
+	if cmp == 0 {
+		return lower.op == cmpGt || upper.op == cmpLt
+	}
+	return true
+}
+
+// compareLiterals compares two literal values of the same underlying Go
+// type, returning ok=false if they can't be compared this way (different
+// types, or a type this pass doesn't know how to order).
+func compareLiterals(a, b interface{}) (cmp int, ok bool) {
+	switch av := a.(type) {
+	case int64:
+		bv, ok := b.(int64)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(av, bv), true
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(av, bv), true
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return compareOrdered(av, bv), true
+	default:
+		return 0, false
+	}
+}
+
+func compareOrdered[T int64 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"fmt"
+)
+
+// SelectLockType describes the pessimistic-locking mode requested by a
+// SELECT ... FOR UPDATE / LOCK IN SHARE MODE clause.
+type SelectLockType uint8
+
+const (
+	SelectLockType_None SelectLockType = iota
+	SelectLockType_ForUpdate
+	SelectLockType_ForShare
+	SelectLockType_ForUpdateNoWait
+	SelectLockType_ForUpdateSkipLocked
+)
+
+func (t SelectLockType) String() string {
+	switch t {
+	case SelectLockType_ForUpdate:
+		return "for update"
+	case SelectLockType_ForShare:
+		return "for share"
+	case SelectLockType_ForUpdateNoWait:
+		return "for update nowait"
+	case SelectLockType_ForUpdateSkipLocked:
+		return "for update skip locked"
+	default:
+		return "none"
+	}
+}
+
+// Lock is a RelExpr wrapping a child group with a row-level locking mode. It
+// is memoized rather than lowered directly into the child because a lock
+// above a join must be pushed down onto the base tables named in the
+// clause's OF <table> list (or every base table, if the list is empty)
+// before the physical builder can ask each underlying sql.LockingTable to
+// acquire the lock during iteration.
+type Lock struct {
+	*relBase
+	Child *ExprGroup
+	Typ   SelectLockType
+	// Of is the set of table names the lock applies to, lower-cased. An
+	// empty set means every base table referenced by Child is locked.
+	Of map[string]bool
+}
+
+var _ RelExpr = (*Lock)(nil)
+
+func (r *Lock) Children() []*ExprGroup {
+	return []*ExprGroup{r.Child}
+}
+
+func (r *Lock) String() string {
+	return fmt.Sprintf("lock: (%s) %s", r.Typ, r.Child.String())
+}
+
+// MemoizeLock creates a Lock group wrapping |child|.
+func (m *Memo) MemoizeLock(grp, child *ExprGroup, typ SelectLockType, of map[string]bool) *ExprGroup {
+	rel := &Lock{
+		relBase: &relBase{},
+		Child:   child,
+		Typ:     typ,
+		Of:      of,
+	}
+	if grp == nil {
+		return m.NewExprGroup(rel)
+	}
+	rel.g = grp
+	grp.Prepend(rel)
+	return grp
+}
+
+// locksTable returns whether a Lock's OF list (or the absence of one) covers
+// the named base table.
+func (r *Lock) locksTable(name string) bool {
+	if len(r.Of) == 0 {
+		return true
+	}
+	return r.Of[name]
+}
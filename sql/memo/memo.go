@@ -40,7 +40,19 @@ type Memo struct {
 	cnt  uint16
 	root *ExprGroup
 
-	hints *joinHints
+	hints      *joinHints
+	negHints   *negativeHints
+	mergeHints *mergeHints
+
+	// aggHint is set by HintTypeHashAgg/HintTypeStreamAgg and consulted by updateBest the same way join-operator
+	// hints are: it forces costing to prefer the named physical alternative for every Aggregation group, falling
+	// back to the normal cost comparison for a group where that alternative was never memoized.
+	aggHint AggPhysical
+
+	// indexScanLookup is set by the index-selection pass so OR-clause
+	// splitting (see or_splitting.go) can reuse its single-predicate
+	// lookup logic per disjunct.
+	indexScanLookup func(source SourceRel, filter sql.Expression) *IndexScan
 
 	c         Coster
 	statsProv sql.StatsProvider
@@ -52,7 +64,7 @@ type Memo struct {
 }
 
 func NewMemo(ctx *sql.Context, stats sql.StatsProvider, s *plan.Scope, scopeLen int, cost Coster) *Memo {
-	return &Memo{
+	m := &Memo{
 		Ctx:        ctx,
 		c:          cost,
 		statsProv:  stats,
@@ -61,6 +73,8 @@ func NewMemo(ctx *sql.Context, stats sql.StatsProvider, s *plan.Scope, scopeLen
 		TableProps: newTableProps(),
 		hints:      &joinHints{},
 	}
+	m.applySessionJoinOperatorFlags(ctx)
+	return m
 }
 
 type MemoErr struct {
@@ -96,6 +110,7 @@ func (m *Memo) NewExprGroup(rel exprType) *ExprGroup {
 }
 
 func (m *Memo) memoizeSourceRel(rel SourceRel) *ExprGroup {
+	m.trackSourceReference(rel.Name())
 	grp := m.NewExprGroup(rel)
 	return grp
 }
@@ -167,6 +182,36 @@ func (m *Memo) MemoizeLookupJoin(grp, left, right *ExprGroup, op plan.JoinType,
 	return grp
 }
 
+// HintTypeIndexHashJoin marks ApplyHint that the INDEX_HASH_JOIN hint requested an IndexHashJoin for the
+// named table pair, the same way the other positive join-algorithm hints request their own operator.
+const HintTypeIndexHashJoin HintType = iota + 300
+
+// MemoizeIndexHashJoin creates an index hash join: like a lookup join it probes the inner side through
+// lookup's index rather than materializing it, but like a hash join it batches outer rows (keyed on
+// leftAttrs/rightAttrs) so a run of duplicate outer keys shares a single inner lookup instead of repeating
+// one lookup per outer row. This is the operator an INDEX_HASH_JOIN hint requests.
+func (m *Memo) MemoizeIndexHashJoin(grp, left, right *ExprGroup, op plan.JoinType, filter []sql.Expression, lookup *IndexScan, leftAttrs, rightAttrs []*ExprGroup) *ExprGroup {
+	newJoin := &IndexHashJoin{
+		JoinBase: &JoinBase{
+			relBase: &relBase{},
+			Left:    left,
+			Right:   right,
+			Op:      op.AsLookup(),
+			Filter:  filter,
+		},
+		Lookup:     lookup,
+		LeftAttrs:  leftAttrs,
+		RightAttrs: rightAttrs,
+	}
+
+	if grp == nil {
+		return m.NewExprGroup(newJoin)
+	}
+	newJoin.g = grp
+	grp.Prepend(newJoin)
+	return grp
+}
+
 // MemoizeConcatLookupJoin creates a lookup join over a set of disjunctions.
 // If a LOOKUP_JOIN simulates x = v1, a concat lookup performs x in (v1, v2, v3, ...)
 func (m *Memo) MemoizeConcatLookupJoin(grp, left, right *ExprGroup, op plan.JoinType, filter []sql.Expression, lookups []*IndexScan) *ExprGroup {
@@ -333,7 +378,18 @@ func (m *Memo) optimizeMemoGroup(grp *ExprGroup) error {
 		return nil
 	}
 
+	var hadCandidate, sawLegal bool
 	for n != nil {
+		hadCandidate = true
+		if m.negHints.forbids(n) {
+			// Skip every forbidden physical operator, even the last one left
+			// in the group; updateBest never consults negHints, so letting a
+			// forbidden n fall through here would let it become grp.Best.
+			n = n.Next()
+			continue
+		}
+		sawLegal = true
+
 		var cost float64
 		for _, g := range n.Children() {
 			err = m.optimizeMemoGroup(g)
@@ -367,6 +423,10 @@ func (m *Memo) optimizeMemoGroup(grp *ExprGroup) error {
 		n = n.Next()
 	}
 
+	if hadCandidate && !sawLegal {
+		return fmt.Errorf("memo: every physical alternative for this group is forbidden by a negative join hint (e.g. NO_HASH_JOIN, NO_MERGE_JOIN)")
+	}
+
 	// Certain "best" groups are incompatible.
 	grp.fixConflicts()
 
@@ -381,6 +441,18 @@ func (m *Memo) optimizeMemoGroup(grp *ExprGroup) error {
 // hint corresponds to  no valid plan. Ordering is applied as a global
 // rather than a local property.
 func (m *Memo) updateBest(grp *ExprGroup, n RelExpr, cost float64) {
+	if agg, ok := n.(*Aggregation); ok && m.aggHint != AggPhysicalUnknown {
+		if agg.Physical == m.aggHint {
+			grp.Best = n
+			grp.Cost = cost
+			grp.HintOk = true
+			return
+		}
+		if grp.Best == nil || !grp.HintOk {
+			grp.updateBest(n, cost)
+		}
+		return
+	}
 	if m.hints != nil {
 		if m.hints.satisfiedBy(n) {
 			if !grp.HintOk {
@@ -439,12 +511,38 @@ func (m *Memo) ApplyHint(hint Hint) {
 	case HintTypeJoinOrder:
 		m.WithJoinOrder(hint.Args)
 	case HintTypeJoinFixedOrder:
-	case HintTypeInnerJoin, HintTypeMergeJoin, HintTypeLookupJoin, HintTypeHashJoin, HintTypeSemiJoin, HintTypeAntiJoin, HintTypeLeftOuterLookupJoin:
+		// JOIN_FIXED_ORDER and STRAIGHT_JOIN both ask the planner to preserve the as-written table order
+		// rather than run the DP-based reorder search; WithStraightJoin pins exactly that order.
+		m.WithStraightJoin()
+	case HintTypeInnerJoin, HintTypeMergeJoin, HintTypeLookupJoin, HintTypeHashJoin, HintTypeSemiJoin, HintTypeAntiJoin, HintTypeLeftOuterLookupJoin, HintTypeIndexHashJoin:
 		m.WithJoinOp(hint.Typ, hint.Args[0], hint.Args[1])
+	case HintTypeNoHashJoin:
+		m.withNegativeJoinOpHint(NegativeJoinHintNoHashJoin, hint.Args)
+	case HintTypeNoMergeJoin:
+		m.withNegativeJoinOpHint(NegativeJoinHintNoMergeJoin, hint.Args)
+	case HintTypeNoLookupJoin:
+		m.withNegativeJoinOpHint(NegativeJoinHintNoLookupJoin, hint.Args)
+	case HintTypeNoIndexJoin:
+		m.withNegativeJoinOpHint(NegativeJoinHintNoIndexJoin, hint.Args)
+	case HintTypeHashAgg:
+		m.aggHint = AggPhysicalHash
+	case HintTypeStreamAgg:
+		m.aggHint = AggPhysicalStream
 	default:
 	}
 }
 
+// withNegativeJoinOpHint applies a negative join-operator hint parsed with zero or two table arguments: e.g.
+// plain NO_HASH_JOIN forbids the operator query-wide, while NO_HASH_JOIN(t1,t2) forbids it only for that
+// table pair.
+func (m *Memo) withNegativeJoinOpHint(hint NegativeJoinHint, args []string) {
+	if len(args) < 2 {
+		m.WithNegativeJoinHint(hint, "", "")
+		return
+	}
+	m.WithNegativeJoinHint(hint, args[0], args[1])
+}
+
 func (m *Memo) WithJoinOrder(tables []string) {
 	// order maps groupId -> table dependencies
 	order := make(map[sql.TableId]uint64)
@@ -520,6 +618,10 @@ func (m *Memo) String() string {
 type tableProps struct {
 	grpToName map[GroupId]string
 	nameToGrp map[string]GroupId
+	// order lists each table name in the sequence addTable first saw it, i.e. the order sources appear in
+	// the FROM clause, left to right, before any join reordering - what a STRAIGHT_JOIN query asks the
+	// planner to preserve.
+	order []string
 }
 
 func newTableProps() *tableProps {
@@ -530,6 +632,9 @@ func newTableProps() *tableProps {
 }
 
 func (p *tableProps) addTable(n string, id GroupId) {
+	if _, ok := p.nameToGrp[n]; !ok {
+		p.order = append(p.order, n)
+	}
 	p.grpToName[id] = n
 	p.nameToGrp[n] = id
 }
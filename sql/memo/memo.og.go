@@ -102,6 +102,24 @@ func (r *LookupJoin) JoinPrivate() *JoinBase {
 	return r.JoinBase
 }
 
+type IndexHashJoin struct {
+	*JoinBase
+	Lookup     *Lookup
+	RightAttrs []*ExprGroup
+	LeftAttrs  []*ExprGroup
+}
+
+var _ RelExpr = (*IndexHashJoin)(nil)
+var _ JoinRel = (*IndexHashJoin)(nil)
+
+func (r *IndexHashJoin) String() string {
+	return FormatExpr(r)
+}
+
+func (r *IndexHashJoin) JoinPrivate() *JoinBase {
+	return r.JoinBase
+}
+
 type ConcatJoin struct {
 	*JoinBase
 	Concat []*Lookup
@@ -0,0 +1,96 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// mergeHintWarningCode is the warning code attached to an ignored MERGE(...) hint. MySQL has no dedicated
+// code for an optimizer hint it chose not to honor, so this follows the convention the rest of the hint
+// handling in this package already uses of failing soft (silently for JOIN_ORDER, with a warning here)
+// rather than erroring the query.
+const mergeHintWarningCode = 1105
+
+// mergeHints tracks the CTE/subquery-alias names a MERGE(...) hint asked the optimizer to inline into the
+// enclosing join tree, alongside how many times each name is actually referenced in the query - discovered
+// as memoizeSourceRel registers every source as it's added to the memo - so a name referenced more than once
+// can be rejected per MERGE's single-reference requirement.
+type mergeHints struct {
+	requested map[string]bool
+	refCount  map[string]int
+}
+
+func newMergeHints() *mergeHints {
+	return &mergeHints{
+		requested: make(map[string]bool),
+		refCount:  make(map[string]int),
+	}
+}
+
+// WithMergeHint records that a /*+ MERGE(name) */ hint asked for name's CTE or subquery-alias body to be
+// spliced into the enclosing join tree - so its base tables take part in join ordering, predicate pushdown,
+// and merge/lookup-join selection with the outer relations - instead of running as an opaque node.
+func (m *Memo) WithMergeHint(name string) {
+	if m.mergeHints == nil {
+		m.mergeHints = newMergeHints()
+	}
+	m.mergeHints.requested[strings.ToLower(name)] = true
+}
+
+// trackSourceReference records one more reference to name - a table, CTE, or subquery alias named in the
+// query's FROM clause - so ShouldMergeSource can tell a CTE referenced exactly once from one referenced
+// repeatedly.
+func (m *Memo) trackSourceReference(name string) {
+	if m.mergeHints == nil {
+		return
+	}
+	m.mergeHints.refCount[strings.ToLower(name)]++
+}
+
+// ShouldMergeSource reports whether name's CTE or subquery-alias body should be inlined into the enclosing
+// join tree in place of an opaque SubqueryAlias/RecursiveCte leaf: a MERGE(name) hint was given, name is
+// referenced exactly once in the query, and recursive is false (a recursive CTE's body refers to its own
+// result and can't be spliced into a single join tree). When the hint can't be honored, ShouldMergeSource
+// raises a warning on ctx and returns false rather than failing the query.
+func (m *Memo) ShouldMergeSource(ctx *sql.Context, name string, recursive bool) bool {
+	if m.mergeHints == nil {
+		return false
+	}
+	key := strings.ToLower(name)
+	if !m.mergeHints.requested[key] {
+		return false
+	}
+	if recursive {
+		m.warnMergeHintIgnored(ctx, name, "recursive CTEs cannot be merged into the enclosing join")
+		return false
+	}
+	if m.mergeHints.refCount[key] > 1 {
+		m.warnMergeHintIgnored(ctx, name, fmt.Sprintf("%s is referenced more than once in this query", name))
+		return false
+	}
+	return true
+}
+
+func (m *Memo) warnMergeHintIgnored(ctx *sql.Context, name, reason string) {
+	ctx.Session.Warn(&sql.Warning{
+		Level:   "Warning",
+		Code:    mergeHintWarningCode,
+		Message: fmt.Sprintf("MERGE(%s) hint ignored: %s", name, reason),
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// NaturalJoin is a RelExpr representing a SQL NATURAL JOIN. It is kept
+// distinct from InnerJoin/LeftJoin until the schemas of both children are
+// known, so that the join reorderer can still see that the equi-predicates
+// are implicit rather than user supplied. Once the common columns are
+// known, normalizeNaturalJoin rewrites the node into the equivalent
+// InnerJoin or LeftJoin.
+type NaturalJoin struct {
+	*JoinBase
+}
+
+var _ RelExpr = (*NaturalJoin)(nil)
+var _ JoinRel = (*NaturalJoin)(nil)
+
+func (r *NaturalJoin) String() string {
+	return FormatExpr(r)
+}
+
+func (r *NaturalJoin) JoinPrivate() *JoinBase {
+	return r.JoinBase
+}
+
+// MemoizeNaturalJoin creates a NaturalJoin group. The filter, if any, is the
+// USING/ON condition explicitly provided alongside NATURAL -- MySQL rejects
+// this combination at parse time, but we accept it here so the memo rule can
+// give a precise error.
+func (m *Memo) MemoizeNaturalJoin(grp, left, right *ExprGroup, op plan.JoinType, filter []sql.Expression) *ExprGroup {
+	newJoin := &NaturalJoin{
+		JoinBase: &JoinBase{
+			relBase: &relBase{},
+			Left:    left,
+			Right:   right,
+			Op:      op,
+			Filter:  filter,
+		},
+	}
+	if grp == nil {
+		return m.NewExprGroup(newJoin)
+	}
+	newJoin.g = grp
+	grp.Prepend(newJoin)
+	return grp
+}
+
+// normalizeNaturalJoin materializes the implicit equi-predicates of a
+// NATURAL JOIN over the common columns of its children and returns the
+// equivalent InnerJoin (or LeftJoin, for NATURAL LEFT JOIN), built on a copy
+// of |n|'s JoinBase; |n| itself is not modified. Common
+// columns are matched case-insensitively, excluding generated and hidden
+// columns, and MySQL semantics requires each common column to be projected
+// only once (coalesced) rather than once per side -- that deduplication is
+// the caller's responsibility when building the output projection.
+func normalizeNaturalJoin(n *NaturalJoin) (RelExpr, []int, error) {
+	leftCols := n.Left.RelProps.OutputCols()
+	rightCols := n.Right.RelProps.OutputCols()
+
+	var common []int
+	var filters []sql.Expression
+	for li, lc := range leftCols {
+		if lc.Generated != nil || lc.Hidden {
+			continue
+		}
+		for ri, rc := range rightCols {
+			if rc.Generated != nil || rc.Hidden {
+				continue
+			}
+			if !strings.EqualFold(lc.Name, rc.Name) {
+				continue
+			}
+			lGf := expression.NewGetField(li, lc.Type, lc.Name, lc.Nullable)
+			rGf := expression.NewGetField(len(leftCols)+ri, rc.Type, rc.Name, rc.Nullable)
+			filters = append(filters, expression.NewEquals(lGf, rGf))
+			common = append(common, li)
+			break
+		}
+	}
+
+	// A NATURAL JOIN combined with an explicit ON or USING condition is
+	// invalid SQL; return an error so analysis can reject the query outright.
+	if len(n.Filter) > 0 {
+		return nil, nil, sql.ErrUnsupportedSyntax.New("NATURAL JOIN cannot be combined with ON or USING")
+	}
+
+	base := n.JoinBase.Copy()
+	base.Filter = filters
+
+	if n.Op.IsLeftOuter() {
+		return &LeftJoin{JoinBase: base}, common, nil
+	}
+	return &InnerJoin{JoinBase: base}, common, nil
+}
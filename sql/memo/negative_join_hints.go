@@ -0,0 +1,167 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// NegativeJoinHint names a join algorithm the optimizer is forbidden from choosing, e.g. from a
+// /*+ NO_HASH_JOIN(t1,t2) */ hint. Unlike the positive join hints in joinHints (which pin an exact
+// algorithm for a table pair), a negative hint just removes one alternative from consideration, leaving the
+// coster free to choose among whatever remains.
+type NegativeJoinHint uint8
+
+const (
+	NegativeJoinHintNone NegativeJoinHint = iota
+	NegativeJoinHintNoHashJoin
+	NegativeJoinHintNoMergeJoin
+	NegativeJoinHintNoLookupJoin
+	NegativeJoinHintNoSemiJoin
+	NegativeJoinHintNoIndexJoin
+)
+
+// HintTypeNoHashJoin, HintTypeNoMergeJoin, HintTypeNoLookupJoin, and HintTypeNoIndexJoin mark ApplyHint that
+// the named join algorithm must never be chosen, the negative counterpart to the existing positive
+// join-algorithm hints.
+const (
+	HintTypeNoHashJoin HintType = iota + 200
+	HintTypeNoMergeJoin
+	HintTypeNoLookupJoin
+	HintTypeNoIndexJoin
+)
+
+// negativeHintEntry is a single parsed negative join hint, resolved against the query's tables. Left and
+// Right are both zero for a hint written without table arguments (e.g. plain NO_SEMI_JOIN), which applies
+// query-wide instead of to one specific join.
+type negativeHintEntry struct {
+	hint  NegativeJoinHint
+	left  sql.TableId
+	right sql.TableId
+}
+
+// negativeHints tracks the negative join hints parsed for the current query, consulted by
+// optimizeMemoGroup while costing each ExprGroup.
+type negativeHints struct {
+	entries []negativeHintEntry
+}
+
+// tableIdSet collects the table ids of every source table reachable under g.
+func tableIdSet(g *ExprGroup) map[sql.TableId]bool {
+	ids := make(map[sql.TableId]bool)
+	for _, n := range g.RelProps.TableIdNodes() {
+		ids[n.Id()] = true
+	}
+	return ids
+}
+
+// negativeHintKindsFor returns the NegativeJoinHint values that would forbid rel's physical operator. A
+// LookupJoin and an IndexHashJoin are both named by NO_LOOKUP_JOIN and the narrower NO_INDEX_JOIN, since both
+// probe the inner side through an index; a ConcatJoin (a multi-value IN lookup) is only named by
+// NO_LOOKUP_JOIN, since NO_INDEX_JOIN targets the plain single-value index lookup.
+func negativeHintKindsFor(rel RelExpr) []NegativeJoinHint {
+	switch rel.(type) {
+	case *HashJoin:
+		return []NegativeJoinHint{NegativeJoinHintNoHashJoin}
+	case *MergeJoin:
+		return []NegativeJoinHint{NegativeJoinHintNoMergeJoin}
+	case *LookupJoin:
+		return []NegativeJoinHint{NegativeJoinHintNoLookupJoin, NegativeJoinHintNoIndexJoin}
+	case *ConcatJoin:
+		return []NegativeJoinHint{NegativeJoinHintNoLookupJoin}
+	case *IndexHashJoin:
+		return []NegativeJoinHint{NegativeJoinHintNoLookupJoin, NegativeJoinHintNoIndexJoin}
+	case *SemiJoin:
+		return []NegativeJoinHint{NegativeJoinHintNoSemiJoin}
+	default:
+		return nil
+	}
+}
+
+// forbids returns true if nh disallows the physical operator rel represents for the table pair rel joins.
+func (nh *negativeHints) forbids(rel RelExpr) bool {
+	if nh == nil || len(nh.entries) == 0 {
+		return false
+	}
+	kinds := negativeHintKindsFor(rel)
+	if len(kinds) == 0 {
+		return false
+	}
+
+	var leftIds, rightIds map[sql.TableId]bool
+	if jr, ok := rel.(JoinRel); ok {
+		jb := jr.JoinPrivate()
+		leftIds = tableIdSet(jb.Left)
+		rightIds = tableIdSet(jb.Right)
+	}
+
+	for _, e := range nh.entries {
+		if !containsHint(kinds, e.hint) {
+			continue
+		}
+		if e.left == 0 && e.right == 0 {
+			// Written without table arguments: applies everywhere this operator could be chosen.
+			return true
+		}
+		if (leftIds[e.left] && rightIds[e.right]) || (leftIds[e.right] && rightIds[e.left]) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHint(kinds []NegativeJoinHint, hint NegativeJoinHint) bool {
+	for _, k := range kinds {
+		if k == hint {
+			return true
+		}
+	}
+	return false
+}
+
+// WithNegativeJoinHint records a single parsed negative join hint. left and right are the table names the
+// hint was given, or both empty for a hint written without arguments (e.g. plain NO_SEMI_JOIN), which
+// disallows the operator query-wide rather than for one specific join. A hint naming a table that isn't in
+// the query is silently ignored, the same as an unresolvable positive join-order hint.
+func (m *Memo) WithNegativeJoinHint(hint NegativeJoinHint, left, right string) {
+	var lTab, rTab sql.TableId
+	if left != "" || right != "" {
+		for _, n := range m.root.RelProps.TableIdNodes() {
+			if strings.EqualFold(left, n.Name()) {
+				lTab = n.Id()
+			}
+			if strings.EqualFold(right, n.Name()) {
+				rTab = n.Id()
+			}
+		}
+		if lTab == 0 || rTab == 0 {
+			return
+		}
+	}
+	if m.negHints == nil {
+		m.negHints = &negativeHints{}
+	}
+	m.negHints.entries = append(m.negHints.entries, negativeHintEntry{hint: hint, left: lTab, right: rTab})
+}
+
+// WithNegativeJoinHints records a set of query-wide negative join hints - ones written without table
+// arguments - disallowing each named algorithm for every join in the query.
+func (m *Memo) WithNegativeJoinHints(hints []NegativeJoinHint) {
+	for _, h := range hints {
+		m.WithNegativeJoinHint(h, "", "")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// addNullRejectingFilters inspects an equi-join's filter and, for each side that op null-rejects, synthesizes
+// a `col IS NOT NULL` predicate for every nullable join-key column on that side and prepends it to the
+// returned filter list. This lets join/probe operators like RangeHeap and Lookup skip NULL keys outright
+// instead of relying on the comparator to reject them row by row: an inner or semi join null-rejects both
+// sides, while only the non-preserved side of a left or right join does.
+//
+// A column already covered by a predicate elsewhere in filters - including a prior call to this same
+// function - is left alone rather than duplicated.
+func addNullRejectingFilters(op plan.JoinType, left, right *ExprGroup, filters []sql.Expression) []sql.Expression {
+	var extra []sql.Expression
+	if op.IsNullRejecting(true) {
+		extra = append(extra, nullRejectingFiltersForSide(left, filters)...)
+	}
+	if op.IsNullRejecting(false) {
+		extra = append(extra, nullRejectingFiltersForSide(right, filters)...)
+	}
+	if len(extra) == 0 {
+		return filters
+	}
+	return append(extra, filters...)
+}
+
+// nullRejectingFiltersForSide returns a synthesized IS NOT NULL predicate for each nullable output column of
+// side that isn't already constrained by an existing predicate in filters.
+func nullRejectingFiltersForSide(side *ExprGroup, filters []sql.Expression) []sql.Expression {
+	var synthesized []sql.Expression
+	for i, c := range side.RelProps.OutputCols() {
+		if !c.Nullable {
+			continue
+		}
+		gf := expression.NewGetField(i, c.Type, c.Name, c.Nullable)
+		if filtersCoverColumn(filters, gf) {
+			continue
+		}
+		synthesized = append(synthesized, expression.NewNot(expression.NewIsNull(gf)))
+	}
+	return synthesized
+}
+
+// filtersCoverColumn returns true if any conjunct of filters already references gf - a comparison,
+// IS NOT NULL, or any other predicate naming the column is assumed to reject NULLs on it just as well as a
+// synthesized IS NOT NULL would, so there's no reason to add a redundant one.
+func filtersCoverColumn(filters []sql.Expression, gf *expression.GetField) bool {
+	for _, f := range filters {
+		for _, conjunct := range expression.SplitConjunction(f) {
+			if referencesGetField(conjunct, gf.Index()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// referencesGetField reports whether e or any of its children is a GetField naming the given column index.
+func referencesGetField(e sql.Expression, idx int) bool {
+	if gf, ok := e.(*expression.GetField); ok {
+		return gf.Index() == idx
+	}
+	for _, c := range e.Children() {
+		if referencesGetField(c, idx) {
+			return true
+		}
+	}
+	return false
+}
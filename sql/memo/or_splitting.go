@@ -0,0 +1,93 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// splitDisjunction flattens a tree of sql.Or expressions into its
+// individual disjuncts, e.g. `a = 1 OR a = 2 OR b = 3` becomes
+// `[a = 1, a = 2, b = 3]`. Non-Or expressions are returned as a
+// single-element slice.
+func splitDisjunction(e sql.Expression) []sql.Expression {
+	or, ok := e.(interface {
+		Left() sql.Expression
+		Right() sql.Expression
+	})
+	if !ok {
+		return []sql.Expression{e}
+	}
+	if !isOrExpr(e) {
+		return []sql.Expression{e}
+	}
+	return append(splitDisjunction(or.Left()), splitDisjunction(or.Right())...)
+}
+
+func isOrExpr(e sql.Expression) bool {
+	type named interface{ FunctionName() string }
+	type oper interface{ Operator() string }
+	switch v := e.(type) {
+	case oper:
+		return v.Operator() == "OR" || v.Operator() == "||"
+	case named:
+		return v.FunctionName() == "or"
+	default:
+		return false
+	}
+}
+
+// disjunctIndexScans attempts to build an IndexScan for each disjunct of an
+// OR-connected filter independently. If every disjunct resolves to an index
+// scan (the scans may use different indexes and different ranges), the
+// filter as a whole can be served by a ConcatJoin/IndexScan-style union of per-disjunct
+// lookups instead of a full table scan with a post-filter -- the same
+// benefit a hand-written `x = 1 OR x = 2 OR x = 3` => `x IN (1, 2, 3)`
+// rewrite gives, but for predicates an IN-list rewrite can't capture (mixed
+// columns, ranges, etc). It returns nil if any disjunct can't be served by
+// an index.
+func (m *Memo) disjunctIndexScans(source SourceRel, filter sql.Expression) []*IndexScan {
+	disjuncts := splitDisjunction(filter)
+	if len(disjuncts) < 2 {
+		return nil
+	}
+
+	scans := make([]*IndexScan, 0, len(disjuncts))
+	for _, d := range disjuncts {
+		scan := m.indexScanForFilter(source, d)
+		if scan == nil {
+			// Not every branch is index-backed; a partial rewrite would
+			// require re-applying the un-scanned branches as a residual
+			// filter, which the costed alternative (a single full scan +
+			// filter) already covers, so bail rather than duplicate work.
+			return nil
+		}
+		scans = append(scans, scan)
+	}
+	return scans
+}
+
+// indexScanForFilter is a narrow hook populated by the index-selection pass
+// that already exists for single (non-OR) predicates; it is factored out
+// here so disjunctIndexScans can reuse it per-disjunct without duplicating
+// index-matching logic. The zero value (nil) means "no lookup rule
+// registered yet" and disjunctIndexScans treats that the same as "no index
+// available".
+func (m *Memo) indexScanForFilter(source SourceRel, filter sql.Expression) *IndexScan {
+	if m.indexScanLookup == nil {
+		return nil
+	}
+	return m.indexScanLookup(source, filter)
+}
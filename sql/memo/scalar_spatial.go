@@ -0,0 +1,96 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+// STIntersects, STContains, and STWithin are binary spatial predicates
+// comparing the geometries produced by Left and Right. They mirror the
+// generated scalar set (Equal, Lt, ...) so the coster and join reorderer
+// can reason about them the same way, but are hand-maintained here pending
+// a spatial-aware pass of the optgen generator.
+type STIntersects struct {
+	*scalarBase
+	Left  *ExprGroup
+	Right *ExprGroup
+}
+
+var _ ScalarExpr = (*STIntersects)(nil)
+
+func (r *STIntersects) ExprId() ScalarExprId {
+	return ScalarExprSTIntersects
+}
+
+func (r *STIntersects) String() string {
+	return FormatExpr(r)
+}
+
+type STContains struct {
+	*scalarBase
+	Left  *ExprGroup
+	Right *ExprGroup
+}
+
+var _ ScalarExpr = (*STContains)(nil)
+
+func (r *STContains) ExprId() ScalarExprId {
+	return ScalarExprSTContains
+}
+
+func (r *STContains) String() string {
+	return FormatExpr(r)
+}
+
+type STWithin struct {
+	*scalarBase
+	Left  *ExprGroup
+	Right *ExprGroup
+}
+
+var _ ScalarExpr = (*STWithin)(nil)
+
+func (r *STWithin) ExprId() ScalarExprId {
+	return ScalarExprSTWithin
+}
+
+func (r *STWithin) String() string {
+	return FormatExpr(r)
+}
+
+// STDWithin is a ternary spatial predicate: true if Left and Right are
+// within Distance of one another.
+type STDWithin struct {
+	*scalarBase
+	Left     *ExprGroup
+	Right    *ExprGroup
+	Distance *ExprGroup
+}
+
+var _ ScalarExpr = (*STDWithin)(nil)
+
+func (r *STDWithin) ExprId() ScalarExprId {
+	return ScalarExprSTDWithin
+}
+
+func (r *STDWithin) String() string {
+	return FormatExpr(r)
+}
+
+// ScalarExprSTIntersects..ScalarExprSTDWithin extend the generated
+// ScalarExprId enum with the new spatial predicate kinds.
+const (
+	ScalarExprSTIntersects ScalarExprId = iota + 1000
+	ScalarExprSTContains
+	ScalarExprSTWithin
+	ScalarExprSTDWithin
+)
@@ -0,0 +1,68 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// sessionJoinOperatorFlags pairs each gms_opt_enable_* session variable with the NegativeJoinHint that
+// disallows its physical operator. Turning one OFF behaves exactly like a query-wide negative hint (e.g.
+// NO_HASH_JOIN with no table arguments) for every query this session runs, until the variable is turned back
+// on: the planner skips that operator and falls back to the next-best legal alternative, and returns an error
+// only for a group in which every alternative is disallowed.
+var sessionJoinOperatorFlags = []struct {
+	varName string
+	hint    NegativeJoinHint
+}{
+	{sql.OptEnableHashJoinSessionVar, NegativeJoinHintNoHashJoin},
+	{sql.OptEnableMergeJoinSessionVar, NegativeJoinHintNoMergeJoin},
+	{sql.OptEnableLookupJoinSessionVar, NegativeJoinHintNoLookupJoin},
+	{sql.OptEnableSemiJoinTransformSessionVar, NegativeJoinHintNoSemiJoin},
+	{sql.TiDBOptEnableHashJoinSessionVar, NegativeJoinHintNoHashJoin},
+	{sql.TiDBOptEnableMergeJoinSessionVar, NegativeJoinHintNoMergeJoin},
+	{sql.TiDBOptEnableLookupJoinSessionVar, NegativeJoinHintNoLookupJoin},
+}
+
+// applySessionJoinOperatorFlags reads this session's gms_opt_enable_* variables and records a query-wide
+// negative join hint for each one that's OFF, reusing the same forbid-and-fall-back machinery a
+// NO_HASH_JOIN-style query hint uses. A variable whose value can't be read (e.g. it was never registered in
+// this build) is treated as ON, the same as its documented default.
+func (m *Memo) applySessionJoinOperatorFlags(ctx *sql.Context) {
+	for _, f := range sessionJoinOperatorFlags {
+		val, err := ctx.GetSessionVariable(ctx, f.varName)
+		if err != nil {
+			continue
+		}
+		if sessionVarIsOff(val) {
+			m.WithNegativeJoinHint(f.hint, "", "")
+		}
+	}
+}
+
+// sessionVarIsOff reports whether val - the value of a boolean system variable, stored internally as an
+// int8(0)/int8(1) the way MySQL's ON/OFF system variables are - is OFF.
+func sessionVarIsOff(val interface{}) bool {
+	switch v := val.(type) {
+	case int8:
+		return v == 0
+	case int64:
+		return v == 0
+	case bool:
+		return !v
+	default:
+		return false
+	}
+}
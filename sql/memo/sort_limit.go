@@ -0,0 +1,134 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Sort, Limit, TopN, and TableDual were previously built directly as
+// sql.Nodes wrapping the best memo plan after OptimizeRoot returned,
+// which meant the coster never saw them: a child that happened to already
+// be sorted on the ORDER BY columns (e.g. because a MergeJoin needed the
+// same order) couldn't avoid a redundant sort, and LIMIT couldn't
+// influence the coster's row count estimates for its child. Memoizing them
+// lets EstimateCost see the whole tree.
+
+// Sort is the logical RelExpr for ORDER BY.
+type Sort struct {
+	*relBase
+	Child      *ExprGroup
+	SortFields []sql.Expression
+	Descending []bool
+}
+
+var _ RelExpr = (*Sort)(nil)
+
+func (r *Sort) Children() []*ExprGroup { return []*ExprGroup{r.Child} }
+func (r *Sort) String() string         { return FormatExpr(r) }
+
+// MemoizeSort memoizes a Sort over child. If child's group is already
+// known to produce rows in this order (tracked by the coster via
+// sortedInputs), the coster should prefer this node's cost to be near
+// zero rather than re-deriving the sort.
+func (m *Memo) MemoizeSort(grp, child *ExprGroup, sortFields []sql.Expression, descending []bool) *ExprGroup {
+	rel := &Sort{relBase: &relBase{}, Child: child, SortFields: sortFields, Descending: descending}
+	if grp == nil {
+		return m.NewExprGroup(rel)
+	}
+	rel.g = grp
+	grp.Prepend(rel)
+	return grp
+}
+
+// Limit is the logical RelExpr for LIMIT [OFFSET].
+type Limit struct {
+	*relBase
+	Child  *ExprGroup
+	Limit  sql.Expression
+	Offset sql.Expression
+}
+
+var _ RelExpr = (*Limit)(nil)
+
+func (r *Limit) Children() []*ExprGroup { return []*ExprGroup{r.Child} }
+func (r *Limit) String() string         { return FormatExpr(r) }
+
+// MemoizeLimit memoizes a Limit over child.
+func (m *Memo) MemoizeLimit(grp, child *ExprGroup, limit, offset sql.Expression) *ExprGroup {
+	rel := &Limit{relBase: &relBase{}, Child: child, Limit: limit, Offset: offset}
+	if grp == nil {
+		return m.NewExprGroup(rel)
+	}
+	rel.g = grp
+	grp.Prepend(rel)
+	return grp
+}
+
+// TopN is the physical alternative to Sort+Limit: rather than fully
+// sorting child, it maintains a bounded heap of the smallest/largest N
+// rows seen so far. It is only a valid alternative when Limit's Offset is
+// nil, since an offset requires knowing the exact rank of every row.
+type TopN struct {
+	*relBase
+	Child      *ExprGroup
+	SortFields []sql.Expression
+	Descending []bool
+	N          sql.Expression
+}
+
+var _ RelExpr = (*TopN)(nil)
+
+func (r *TopN) Children() []*ExprGroup { return []*ExprGroup{r.Child} }
+func (r *TopN) String() string         { return FormatExpr(r) }
+
+// MemoizeTopN memoizes a TopN into the same group as an equivalent Sort+
+// Limit pair, so the coster can pick whichever is cheaper.
+func (m *Memo) MemoizeTopN(grp, child *ExprGroup, sortFields []sql.Expression, descending []bool, n sql.Expression) *ExprGroup {
+	rel := &TopN{relBase: &relBase{}, Child: child, SortFields: sortFields, Descending: descending, N: n}
+	if grp == nil {
+		return m.NewExprGroup(rel)
+	}
+	rel.g = grp
+	grp.Prepend(rel)
+	return grp
+}
+
+// TableDual is a SourceRel producing exactly one row with no columns (or
+// zero rows, for the "dual" with a false filter folded in). It replaces
+// literal-only subtrees like `SELECT 1` or a provably-empty filter so the
+// coster doesn't need to special-case plans with no real source table.
+type TableDual struct {
+	*sourceBase
+	name string
+	row  bool
+}
+
+var _ RelExpr = (*TableDual)(nil)
+var _ SourceRel = (*TableDual)(nil)
+
+func (r *TableDual) Children() []*ExprGroup { return nil }
+func (r *TableDual) String() string         { return FormatExpr(r) }
+func (r *TableDual) Name() string           { return r.name }
+func (r *TableDual) OutputCols() sql.Schema { return nil }
+func (r *TableDual) TableId() TableId       { return TableIdForSource(r.g.Id) }
+
+// MemoizeTableDual creates a TableDual source producing a single row if
+// |row| is true, or zero rows otherwise (the latter is used when the
+// optimizer proves a subtree's filter is always false).
+func (m *Memo) MemoizeTableDual(row bool) *ExprGroup {
+	rel := &TableDual{sourceBase: &sourceBase{relBase: &relBase{}}, name: "dual", row: row}
+	return m.memoizeSourceRel(rel)
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// SpatialJoin is a JoinRel, analogous to LookupJoin/MergeJoin, chosen when
+// one side of the join has a spatial index over a GEOMETRY column and the
+// join predicate is a spatial function (ST_Intersects, ST_Contains,
+// ST_Within, ST_DWithin). At execution time the outer side is driven
+// row-by-row; for each outer row an MBR probe against the inner
+// sql.SpatialIndex yields candidate rows, which are then rechecked against
+// the exact predicate before being returned.
+type SpatialJoin struct {
+	*JoinBase
+	// Index is the spatial index probed on the inner side.
+	Index *Index
+	// Op is the spatial predicate driving the probe.
+	Op SpatialOp
+}
+
+var _ RelExpr = (*SpatialJoin)(nil)
+var _ JoinRel = (*SpatialJoin)(nil)
+
+func (r *SpatialJoin) String() string {
+	return FormatExpr(r)
+}
+
+func (r *SpatialJoin) JoinPrivate() *JoinBase {
+	return r.JoinBase
+}
+
+// SpatialOp identifies which spatial predicate drives a SpatialJoin probe.
+type SpatialOp uint8
+
+const (
+	SpatialOp_STIntersects SpatialOp = iota
+	SpatialOp_STContains
+	SpatialOp_STWithin
+	SpatialOp_STDWithin
+)
+
+// MemoizeSpatialJoin creates a SpatialJoin group probing |index| on the
+// inner (right) side of the join.
+func (m *Memo) MemoizeSpatialJoin(grp, left, right *ExprGroup, op plan.JoinType, filter []sql.Expression, index *Index, spatialOp SpatialOp) *ExprGroup {
+	newJoin := &SpatialJoin{
+		JoinBase: &JoinBase{
+			relBase: &relBase{},
+			Left:    left,
+			Right:   right,
+			Op:      op,
+			Filter:  filter,
+		},
+		Index: index,
+		Op:    spatialOp,
+	}
+	if grp == nil {
+		return m.NewExprGroup(newJoin)
+	}
+	newJoin.g = grp
+	grp.Prepend(newJoin)
+	return grp
+}
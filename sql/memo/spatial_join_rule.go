@@ -0,0 +1,81 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// addSpatialJoins inspects a join's filter for spatial predicates
+// (STIntersects, STContains, STWithin, STDWithin) and, for each one whose
+// inner side carries a matching sql.SpatialIndex, memoizes a SpatialJoin
+// alternative into grp alongside whatever join algorithms were already
+// found. It is the spatial counterpart of the equality-predicate lookup-
+// join rule: same idea of "is there an index that makes probing cheaper
+// than a full scan", but keyed off a bounding-box test instead of equality.
+func (m *Memo) addSpatialJoins(grp, left, right *ExprGroup, op plan.JoinType, filters []sql.Expression) {
+	rightSource, ok := right.First.(SourceRel)
+	if !ok {
+		return
+	}
+
+	for _, f := range filters {
+		spatialOp, ok := classifySpatialPredicate(f)
+		if !ok {
+			continue
+		}
+		idx := findSpatialIndex(rightSource)
+		if idx == nil {
+			continue
+		}
+		m.MemoizeSpatialJoin(grp, left, right, op, filters, idx, spatialOp)
+	}
+}
+
+// classifySpatialPredicate identifies whether |e| is a call to one of the
+// spatial predicate functions this rule knows how to serve from an index,
+// returning the corresponding SpatialOp.
+func classifySpatialPredicate(e sql.Expression) (SpatialOp, bool) {
+	fn, ok := e.(interface{ FunctionName() string })
+	if !ok {
+		return 0, false
+	}
+	switch fn.FunctionName() {
+	case "st_intersects":
+		return SpatialOp_STIntersects, true
+	case "st_contains":
+		return SpatialOp_STContains, true
+	case "st_within":
+		return SpatialOp_STWithin, true
+	case "st_dwithin":
+		return SpatialOp_STDWithin, true
+	default:
+		return 0, false
+	}
+}
+
+// findSpatialIndex returns a spatial index registered on source, if any.
+func findSpatialIndex(source SourceRel) *Index {
+	for _, idx := range source.Indexes() {
+		if idx == nil || idx.idx == nil {
+			continue
+		}
+		if s, ok := idx.idx.(interface{ IsSpatial() bool }); ok && s.IsSpatial() {
+			return idx
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+// WithStraightJoin pins the join order to the order sources were encountered while building this memo - the
+// order they appear in the FROM clause, left to right - and disables the DP-based join reorder search, the
+// same effect a /*+ JOIN_FIXED_ORDER */ hint has. This is what both the STRAIGHT_JOIN hint and the
+// `SELECT STRAIGHT_JOIN ...` keyword reduce to: the analyzer rule that recognizes either form calls
+// WithStraightJoin instead of letting the cost-based search run, while physical-operator selection (merge vs
+// hash vs lookup) for each fixed pair proceeds exactly as it would otherwise.
+func (m *Memo) WithStraightJoin() {
+	m.WithJoinOrder(m.TableProps.order)
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memo
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// UnionScan wraps a SourceRel that has uncommitted, buffered writes pending
+// against it within the current transaction: rows committed to storage are
+// scanned normally, and rows from the session's write buffer are unioned
+// in. Previously this forced join planning to fall back to a plain
+// InnerJoin/LeftJoin over the whole table, because the memo had no source
+// shape that still exposed the underlying table's indexes once the buffer
+// was unioned in. Representing it as its own SourceRel lets LookupJoin and
+// MergeJoin see through to Table's indexes while still accounting for the
+// buffered rows at execution time.
+type UnionScan struct {
+	*sourceBase
+	// Table is the durable-storage side of the scan.
+	Table SourceRel
+}
+
+var _ RelExpr = (*UnionScan)(nil)
+var _ SourceRel = (*UnionScan)(nil)
+
+func (r *UnionScan) Children() []*ExprGroup { return []*ExprGroup{r.Table.Group()} }
+func (r *UnionScan) String() string         { return FormatExpr(r) }
+func (r *UnionScan) Name() string           { return r.Table.Name() }
+func (r *UnionScan) OutputCols() sql.Schema { return r.Table.OutputCols() }
+func (r *UnionScan) TableId() sql.TableId   { return r.Table.TableId() }
+
+// MemoizeUnionScan wraps |table|'s group in a UnionScan, carrying over its
+// indexes so join planning can still consider LookupJoin/MergeJoin against
+// it. |table| must already be memoized as a SourceRel.
+func (m *Memo) MemoizeUnionScan(table SourceRel) *ExprGroup {
+	rel := &UnionScan{
+		sourceBase: &sourceBase{relBase: &relBase{}, indexes: table.Indexes()},
+		Table:      table,
+	}
+	return m.memoizeSourceRel(rel)
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import "github.com/dolthub/go-mysql-server/sql/types"
+
+// Names of the session variables the join planner consults to globally disable a physical join operator for
+// a session, the same way a NO_HASH_JOIN-style hint disables it for a single query. Each defaults to ON, so
+// turning one OFF is an opt-in, session-wide escape hatch for an operator the optimizer otherwise considers.
+const (
+	OptEnableHashJoinSessionVar          = "gms_opt_enable_hash_join"
+	OptEnableMergeJoinSessionVar         = "gms_opt_enable_merge_join"
+	OptEnableLookupJoinSessionVar        = "gms_opt_enable_lookup_join"
+	OptEnableSemiJoinTransformSessionVar = "gms_opt_enable_semi_join_transform"
+)
+
+// Aliases for the same knobs above, spelled the way TiDB names them, for tools and ORMs that probe for
+// tidb_opt_enable_* specifically. They're separate SystemVariables, not a rename - setting one doesn't change
+// the other - but both names disable the identical operator for this session.
+const (
+	TiDBOptEnableHashJoinSessionVar   = "tidb_opt_enable_hash_join"
+	TiDBOptEnableMergeJoinSessionVar  = "tidb_opt_enable_merge_join"
+	TiDBOptEnableLookupJoinSessionVar = "tidb_opt_enable_lookup_join"
+)
+
+func init() {
+	SystemVariables.AddSystemVariables([]SystemVariable{
+		{
+			Name:              OptEnableHashJoinSessionVar,
+			Scope:             GetMysqlScope(SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(OptEnableHashJoinSessionVar),
+			Default:           int8(1),
+		},
+		{
+			Name:              OptEnableMergeJoinSessionVar,
+			Scope:             GetMysqlScope(SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(OptEnableMergeJoinSessionVar),
+			Default:           int8(1),
+		},
+		{
+			Name:              OptEnableLookupJoinSessionVar,
+			Scope:             GetMysqlScope(SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(OptEnableLookupJoinSessionVar),
+			Default:           int8(1),
+		},
+		{
+			Name:              OptEnableSemiJoinTransformSessionVar,
+			Scope:             GetMysqlScope(SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(OptEnableSemiJoinTransformSessionVar),
+			Default:           int8(1),
+		},
+		{
+			Name:              TiDBOptEnableHashJoinSessionVar,
+			Scope:             GetMysqlScope(SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(TiDBOptEnableHashJoinSessionVar),
+			Default:           int8(1),
+		},
+		{
+			Name:              TiDBOptEnableMergeJoinSessionVar,
+			Scope:             GetMysqlScope(SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(TiDBOptEnableMergeJoinSessionVar),
+			Default:           int8(1),
+		},
+		{
+			Name:              TiDBOptEnableLookupJoinSessionVar,
+			Scope:             GetMysqlScope(SystemVariableScope_Session),
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(TiDBOptEnableLookupJoinSessionVar),
+			Default:           int8(1),
+		},
+	})
+}
@@ -0,0 +1,203 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// EliminateCrossJoins finds Filter nodes sitting over a chain of CrossJoins - the plan `FROM a, b, c WHERE
+// a.x=b.x AND b.y=c.y` builds before join planning - and rewrites the subtree into a bushy tree of InnerJoins
+// wherever the filter supplies an equi-predicate connecting two of the cross-joined tables. Tables are
+// grouped into connected components by shared equi-predicates: each component becomes an inner join chain,
+// and any component left with no connecting predicate to the rest is joined to its siblings with a trailing
+// CrossJoin, exactly as if the user had written the query with explicit JOIN ... ON clauses. Filter conjuncts
+// consumed as join conditions are removed from the residual Filter; if none remain, the Filter node itself is
+// dropped.
+func EliminateCrossJoins(ctx *sql.Context, n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(node sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		f, ok := node.(*Filter)
+		if !ok {
+			return node, transform.SameTree, nil
+		}
+		tables, isCrossJoinChain := flattenCrossJoins(f.Child)
+		if !isCrossJoinChain || len(tables) < 2 {
+			return node, transform.SameTree, nil
+		}
+
+		conjuncts := expression.SplitConjunction(f.Expression)
+		rewritten, remaining, changed := buildConnectedJoinTree(tables, conjuncts)
+		if !changed {
+			return node, transform.SameTree, nil
+		}
+		if len(remaining) == 0 {
+			return rewritten, transform.NewTree, nil
+		}
+		return NewFilter(expression.JoinAnd(remaining...), rewritten), transform.NewTree, nil
+	})
+}
+
+// flattenCrossJoins walks down a chain of nested CrossJoin nodes and returns its leaves in left-to-right
+// order. ok is false if n is not itself a CrossJoin - a lone table under a Filter has nothing to rewrite.
+func flattenCrossJoins(n sql.Node) (tables []sql.Node, ok bool) {
+	cj, isCrossJoin := n.(*CrossJoin)
+	if !isCrossJoin {
+		return nil, false
+	}
+	var collect func(n sql.Node) []sql.Node
+	collect = func(n sql.Node) []sql.Node {
+		if cj, ok := n.(*CrossJoin); ok {
+			return append(collect(cj.Left()), collect(cj.Right())...)
+		}
+		return []sql.Node{n}
+	}
+	return append(collect(cj.Left()), collect(cj.Right())...), true
+}
+
+// joinEdge is an equi-predicate conjunct that connects two of the cross-joined tables.
+type joinEdge struct {
+	a, b   int
+	filter sql.Expression
+	// conj is filter's index in the conjunct list it was taken from.
+	conj int
+}
+
+// buildConnectedJoinTree groups tables into connected components using the equi-predicates in conjuncts,
+// joins each component's tables with InnerJoin using the edges that connect them, and cross-joins the
+// resulting per-component subtrees together. It returns the rewritten tree, the conjuncts that weren't
+// consumed as a join condition, and whether any inner join was actually built.
+func buildConnectedJoinTree(tables []sql.Node, conjuncts []sql.Expression) (sql.Node, []sql.Expression, bool) {
+	offsets := make([]int, len(tables))
+	total := 0
+	for i, t := range tables {
+		offsets[i] = total
+		total += len(t.Schema())
+	}
+	tableOf := func(colIdx int) int {
+		for i := len(offsets) - 1; i >= 0; i-- {
+			if colIdx >= offsets[i] {
+				return i
+			}
+		}
+		return 0
+	}
+
+	var edges []joinEdge
+	used := make([]bool, len(conjuncts))
+	for i, c := range conjuncts {
+		eq, ok := c.(*expression.Equals)
+		if !ok {
+			continue
+		}
+		lgf, lok := eq.Left().(*expression.GetField)
+		rgf, rok := eq.Right().(*expression.GetField)
+		if !lok || !rok {
+			continue
+		}
+		ta, tb := tableOf(lgf.Index()), tableOf(rgf.Index())
+		if ta == tb {
+			continue
+		}
+		edges = append(edges, joinEdge{a: ta, b: tb, filter: c, conj: i})
+	}
+	if len(edges) == 0 {
+		return nil, nil, false
+	}
+
+	parent := make([]int, len(tables))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(x int) int {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	union := func(x, y int) {
+		rx, ry := find(x), find(y)
+		if rx != ry {
+			parent[rx] = ry
+		}
+	}
+	for _, e := range edges {
+		union(e.a, e.b)
+	}
+
+	// Build each component's inner join chain by adding tables one at a time, using whichever edge first
+	// connects that table to the ones already joined.
+	componentSubtree := make(map[int]sql.Node)
+	componentMembers := make(map[int][]int)
+	for i := range tables {
+		root := find(i)
+		componentMembers[root] = append(componentMembers[root], i)
+	}
+	for root, members := range componentMembers {
+		joined := map[int]bool{members[0]: true}
+		subtree := tables[members[0]]
+		remainingMembers := append([]int{}, members[1:]...)
+		for len(remainingMembers) > 0 {
+			progressed := false
+			for idx, m := range remainingMembers {
+				for _, e := range edges {
+					if !((e.a == m && joined[e.b]) || (e.b == m && joined[e.a])) {
+						continue
+					}
+					subtree = NewInnerJoin(subtree, tables[m], e.filter)
+					used[e.conj] = true
+					joined[m] = true
+					remainingMembers = append(remainingMembers[:idx], remainingMembers[idx+1:]...)
+					progressed = true
+					break
+				}
+				if progressed {
+					break
+				}
+			}
+			if !progressed {
+				// Shouldn't happen given union-find connectivity, but avoid looping forever.
+				break
+			}
+		}
+		componentSubtree[root] = subtree
+	}
+
+	// Cross-join the (possibly singleton) component subtrees back together in table order.
+	var result sql.Node
+	seen := make(map[int]bool)
+	for i := range tables {
+		root := find(i)
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		if result == nil {
+			result = componentSubtree[root]
+		} else {
+			result = NewCrossJoin(result, componentSubtree[root])
+		}
+	}
+
+	var remaining []sql.Expression
+	for i, c := range conjuncts {
+		if !used[i] {
+			remaining = append(remaining, c)
+		}
+	}
+	return result, remaining, true
+}
@@ -16,9 +16,13 @@ package plan
 
 import (
 	"fmt"
+	"io"
+	"strings"
+
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/transform"
 )
 
 var ErrDeleteFromNotSupported = errors.NewKind("table doesn't support DELETE FROM")
@@ -27,9 +31,14 @@ var ErrDeleteFromNotSupported = errors.NewKind("table doesn't support DELETE FRO
 type DeleteFrom struct {
 	UnaryNode
 	Targets []sql.Node
+	// Returning holds the Postgres-style `RETURNING expr, ...` expressions, evaluated against each deleted
+	// row (across all targets' columns) after it's been removed. Empty unless the statement had a RETURNING
+	// clause.
+	Returning []sql.Expression
 }
 
 var _ sql.Databaseable = (*DeleteFrom)(nil)
+var _ sql.Expressioner = (*DeleteFrom)(nil)
 
 // NewDeleteFrom creates a DeleteFrom node.
 func NewDeleteFrom(n sql.Node, targets []sql.Node) *DeleteFrom {
@@ -39,6 +48,48 @@ func NewDeleteFrom(n sql.Node, targets []sql.Node) *DeleteFrom {
 	}
 }
 
+// WithReturning returns a new instance of this DeleteFrom with the given RETURNING expressions.
+func (p *DeleteFrom) WithReturning(returning []sql.Expression) *DeleteFrom {
+	newDeleteFrom := *p
+	newDeleteFrom.Returning = returning
+	return &newDeleteFrom
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (p *DeleteFrom) Expressions() []sql.Expression {
+	return p.Returning
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (p *DeleteFrom) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != len(p.Returning) {
+		return nil, sql.ErrInvalidChildrenNumber.New(p, len(exprs), len(p.Returning))
+	}
+	return p.WithReturning(exprs), nil
+}
+
+// Schema implements the Node interface. A DeleteFrom with a RETURNING clause reports the schema of its
+// Returning expressions instead of the default DML result schema, since its RowIter yields one row per
+// deleted tuple rather than draining the child iterator for a row count.
+func (p *DeleteFrom) Schema() sql.Schema {
+	if len(p.Returning) == 0 {
+		return p.UnaryNode.Schema()
+	}
+	schema := make(sql.Schema, len(p.Returning))
+	for i, e := range p.Returning {
+		name := e.String()
+		if nameable, ok := e.(sql.Nameable); ok {
+			name = nameable.Name()
+		}
+		schema[i] = &sql.Column{
+			Name:     name,
+			Type:     e.Type(),
+			Nullable: e.IsNullable(),
+		}
+	}
+	return schema
+}
+
 func GetDeletable(node sql.Node) (sql.DeletableTable, error) {
 	switch node := node.(type) {
 	case sql.DeletableTable:
@@ -96,6 +147,26 @@ func deleteDatabaseHelper(node sql.Node) string {
 	return ""
 }
 
+// deleteDatabaseObject finds the sql.Database a delete target resolves to, the same way deleteDatabaseHelper
+// finds its name, so a target's inbound foreign keys can look up the child tables that reference it.
+// Returns nil if node doesn't resolve to a real database (e.g. it's an UnresolvedTable).
+func deleteDatabaseObject(node sql.Node) sql.Database {
+	switch node := node.(type) {
+	case *IndexedTableAccess:
+		return deleteDatabaseObject(node.ResolvedTable)
+	case *ResolvedTable:
+		return node.Database
+	}
+
+	for _, child := range node.Children() {
+		if db := deleteDatabaseObject(child); db != nil {
+			return db
+		}
+	}
+
+	return nil
+}
+
 // WithTargets returns a new instance of this DeleteFrom, with the specified |targets|.
 func (p *DeleteFrom) WithTargets(targets []sql.Node) sql.Node {
 	newDeleteFrom := *p
@@ -132,40 +203,267 @@ func (p *DeleteFrom) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error)
 		return sql.RowsToRowIter(), nil
 	}
 
-	iter, err := p.Child.RowIter(ctx, row)
+	// The bulk fast paths below never materialize the deleted rows, so they can't serve a RETURNING clause.
+	if len(p.Returning) == 0 {
+		if truncatable, ok := p.truncateTarget(ctx); ok {
+			return &truncateAsDeleteIter{table: truncatable}, nil
+		}
+
+		if rangeDeletable, lookup, ok := p.rangeDeleteTarget(ctx); ok {
+			return &rangeDeleteIter{table: rangeDeletable, lookup: lookup}, nil
+		}
+	}
+
+	// Rewrite p.Child to the narrowest column set deleteIter actually needs, if it's safe to - see
+	// PruneDeleteColumns. This repo has no separate analyzer pass to run the rewrite ahead of time, so it's
+	// applied here, immediately before building the iterator that depends on its result.
+	target := p
+	if prunedNode, ti, err := PruneDeleteColumns(ctx, p); err != nil {
+		return nil, err
+	} else if ti == transform.NewTree {
+		if prunedDelete, ok := prunedNode.(*DeleteFrom); ok {
+			target = prunedDelete
+		}
+	}
+
+	iter, err := target.Child.RowIter(ctx, row)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(p.Targets) == 0 {
-		deletable, err := GetDeletable(p.Child)
+	if len(target.Targets) == 0 {
+		deletable, err := GetDeletable(target.Child)
 		if err != nil {
 			return nil, err
 		}
-		return newDeleteIter(iter, deletable.Schema(), deleterStruct{deletable.Deleter(ctx), 0, len(deletable.Schema())}), nil
+		hooks, _ := deletable.(sql.HookableTable)
+		// Size schemaStart/schemaEnd off target.Child.Schema(), not deletable.Schema(): the row
+		// target.Child.RowIter actually produces is target.Child's own schema, which PruneDeleteColumns can
+		// narrow to less than the target table's full physical schema.
+		schema := target.Child.Schema()
+		start, end := findPosition(schema, deletable.Name())
+		deleter := deletable.Deleter(ctx)
+		// Batching would delay the actual delete past the point a RETURNING clause needs to have observed it.
+		var batcher sql.BatchRowDeleter
+		if len(target.Returning) == 0 {
+			batcher, _ = deleter.(sql.BatchRowDeleter)
+		}
+		return newDeleteIter(ctx, iter, schema, target.Returning, deleterStruct{
+			deleter:     deleter,
+			table:       deletable,
+			db:          deleteDatabaseObject(target.Child),
+			hooks:       hooks,
+			schemaStart: int(start),
+			schemaEnd:   int(end),
+			batcher:     batcher,
+		}), nil
 	} else {
 		// TODO: Validate table wasn't specified twice? validate no multi-db?
-		deleterStructs := make([]deleterStruct, len(p.Targets))
-		for i, target := range p.Targets {
-			deletable, err := GetDeletable(target)
+		deleterStructs := make([]deleterStruct, len(target.Targets))
+		for i, t := range target.Targets {
+			deletable, err := GetDeletable(t)
 			if err != nil {
 				return nil, err
 			}
 			deleter := deletable.Deleter(ctx)
-			start, end := findPosition(p.Child.Schema(), deletable.Name())
-			deleterStructs[i] = deleterStruct{deleter, int(start), int(end)}
+			var batcher sql.BatchRowDeleter
+			if len(target.Returning) == 0 {
+				batcher, _ = deleter.(sql.BatchRowDeleter)
+			}
+			hooks, _ := deletable.(sql.HookableTable)
+			start, end := findPosition(target.Child.Schema(), deletable.Name())
+			deleterStructs[i] = deleterStruct{
+				deleter:     deleter,
+				table:       deletable,
+				db:          deleteDatabaseObject(t),
+				hooks:       hooks,
+				schemaStart: int(start),
+				schemaEnd:   int(end),
+				batcher:     batcher,
+			}
 		}
-		return newDeleteIter(iter, p.Child.Schema(), deleterStructs...), nil
+		return newDeleteIter(ctx, iter, target.Child.Schema(), target.Returning, deleterStructs...), nil
 
 	}
 
 }
 
+// truncateTarget reports the sql.TruncateableTable p.Child can be converted to a TRUNCATE against, for an
+// unconditional "DELETE FROM t" with no WHERE/LIMIT/ORDER BY: p.Child must resolve directly to the table, with no
+// Filter/Limit/Sort/TriggerExecutor node wrapping it (a TriggerExecutor wrapping the table means an AFTER DELETE
+// trigger is registered, which rules out this fast path), and no foreign key referencing the table may have an ON
+// DELETE action other than the default, NO ACTION, or RESTRICT - anything else needs row-by-row delete to cascade
+// or null out the referencing rows. Truncate's own doc comment already covers summing across the table's
+// partitions; this mirrors TRUNCATE TABLE's safety requirements for an equivalent unconditional DELETE.
+func (p *DeleteFrom) truncateTarget(ctx *sql.Context) (sql.TruncateableTable, bool) {
+	if len(p.Targets) > 0 {
+		return nil, false
+	}
+	rt, ok := p.Child.(*ResolvedTable)
+	if !ok {
+		return nil, false
+	}
+	truncatable, ok := rt.Table.(sql.TruncateableTable)
+	if !ok {
+		return nil, false
+	}
+	if fkTable, ok := rt.Table.(sql.ForeignKeyTable); ok {
+		refs, err := fkTable.GetReferencedForeignKeys(ctx)
+		if err != nil {
+			return nil, false
+		}
+		for _, fk := range refs {
+			switch fk.OnDelete {
+			case sql.ForeignKeyReferentialAction_DefaultAction, sql.ForeignKeyReferentialAction_NoAction, sql.ForeignKeyReferentialAction_Restrict:
+				// These actions require every referencing row to already be gone - truncating out from under
+				// one would leave it dangling, so refuse the fast path if any child row still references this
+				// table, the same violation the row-by-row path would raise via fkCascadeExecutor.
+				referenced, err := foreignKeyHasReferencingRows(ctx, deleteDatabaseObject(rt), fk)
+				if err != nil || referenced {
+					return nil, false
+				}
+			default:
+				return nil, false
+			}
+		}
+	}
+	return truncatable, true
+}
+
+// truncateAsDeleteIter runs a TruncateableTable.Truncate call on its first Next call, then reports the number of
+// rows removed by yielding that many empty rows, preserving the convention that the caller counts affected rows by
+// counting rows produced from this iterator.
+type truncateAsDeleteIter struct {
+	table     sql.TruncateableTable
+	started   bool
+	remaining int64
+}
+
+var _ sql.RowIter = (*truncateAsDeleteIter)(nil)
+
+func (t *truncateAsDeleteIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if !t.started {
+		n, err := t.table.Truncate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		t.started = true
+		t.remaining = int64(n)
+	}
+	if t.remaining <= 0 {
+		return nil, io.EOF
+	}
+	t.remaining--
+	return sql.Row{}, nil
+}
+
+func (t *truncateAsDeleteIter) Close(ctx *sql.Context) error {
+	return nil
+}
+
+// rangeDeleteTarget reports the sql.RangeDeletableTable p.Child can delete from directly, and the index lookup that
+// fully covers the predicate, when that fast path is safe to use: p has a single, implicit target that resolves to
+// an IndexedTableAccess (so the predicate is fully covered by the lookup rather than needing a Filter node on top
+// of it - this also covers a PK IN (...) list, which resolves to a single IndexedTableAccess whose lookup spans
+// multiple ranges), and the underlying table needs to see no old row data - it declares no check constraints or
+// HookableTable hooks, and any inbound foreign key referencing it uses the default NO ACTION/RESTRICT behavior
+// rather than CASCADE or SET NULL, mirroring truncateTarget's foreign key check. Any of those forces the row-by-row
+// RowDeleter path instead.
+func (p *DeleteFrom) rangeDeleteTarget(ctx *sql.Context) (sql.RangeDeletableTable, sql.IndexLookup, bool) {
+	if len(p.Targets) > 0 {
+		return nil, sql.IndexLookup{}, false
+	}
+	ita, ok := p.Child.(*IndexedTableAccess)
+	if !ok {
+		return nil, sql.IndexLookup{}, false
+	}
+	deletable, err := GetDeletable(ita.ResolvedTable)
+	if err != nil {
+		return nil, sql.IndexLookup{}, false
+	}
+	if _, needsOldRow := deletable.(sql.CheckTable); needsOldRow {
+		return nil, sql.IndexLookup{}, false
+	}
+	if fkTable, ok := deletable.(sql.ForeignKeyTable); ok {
+		refs, err := fkTable.GetReferencedForeignKeys(ctx)
+		if err != nil {
+			return nil, sql.IndexLookup{}, false
+		}
+		for _, fk := range refs {
+			switch fk.OnDelete {
+			case sql.ForeignKeyReferentialAction_DefaultAction, sql.ForeignKeyReferentialAction_NoAction, sql.ForeignKeyReferentialAction_Restrict:
+				// Same requirement as truncateTarget: a range delete can only skip the row-by-row path if no
+				// child row still references this table, or it would silently leave a dangling foreign key.
+				referenced, err := foreignKeyHasReferencingRows(ctx, deleteDatabaseObject(ita.ResolvedTable), fk)
+				if err != nil || referenced {
+					return nil, sql.IndexLookup{}, false
+				}
+			default:
+				return nil, sql.IndexLookup{}, false
+			}
+		}
+	}
+	if _, needsOldRow := deletable.(sql.HookableTable); needsOldRow {
+		return nil, sql.IndexLookup{}, false
+	}
+	rangeDeletable, ok := deletable.(sql.RangeDeletableTable)
+	if !ok {
+		return nil, sql.IndexLookup{}, false
+	}
+	return rangeDeletable, ita.Lookup, true
+}
+
+// rangeDeleteIter runs a RangeDeletableTable.DeleteRange call on its first Next call, then reports the number of
+// rows deleted by yielding that many empty rows, preserving the convention that the caller counts affected rows by
+// counting rows produced from this iterator - without this iterator ever materializing the deleted rows themselves.
+type rangeDeleteIter struct {
+	table     sql.RangeDeletableTable
+	lookup    sql.IndexLookup
+	started   bool
+	remaining int64
+}
+
+var _ sql.RowIter = (*rangeDeleteIter)(nil)
+
+func (r *rangeDeleteIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if !r.started {
+		n, err := r.table.DeleteRange(ctx, r.lookup, nil)
+		if err != nil {
+			return nil, err
+		}
+		r.started = true
+		r.remaining = n
+	}
+	if r.remaining <= 0 {
+		return nil, io.EOF
+	}
+	r.remaining--
+	return sql.Row{}, nil
+}
+
+func (r *rangeDeleteIter) Close(ctx *sql.Context) error {
+	return nil
+}
+
 // TODO: Rename and document
 type deleterStruct struct {
 	deleter     sql.RowDeleter
+	table       sql.DeletableTable
+	db          sql.Database
+	hooks       sql.HookableTable
 	schemaStart int
 	schemaEnd   int
+
+	// cascade is the fkCascadeExecutor enforcing table's inbound foreign keys, built lazily on the first
+	// call to deleteIter.Next. cascadeBuilt distinguishes "not built yet" from "built, but table has no
+	// inbound foreign keys to enforce" (cascade == nil in both cases).
+	cascade      *fkCascadeExecutor
+	cascadeBuilt bool
+
+	// batcher is non-nil when deleter also implements sql.BatchRowDeleter, in which case deleteIter buffers
+	// rows here instead of calling deleter.Delete for each one.
+	batcher sql.BatchRowDeleter
+	buffer  []sql.Row
 }
 
 func findPosition(schema sql.Schema, name string) (uint, uint) {
@@ -193,19 +491,50 @@ func findPosition(schema sql.Schema, name string) (uint, uint) {
 }
 
 type deleteIter struct {
-	deleters  []deleterStruct
-	schema    sql.Schema
-	childIter sql.RowIter
-	closed    bool
+	deleters    []deleterStruct
+	schema      sql.Schema
+	returning   []sql.Expression
+	childIter   sql.RowIter
+	closed      bool
+	batchSize   int
+	deleteHooks []sql.DeleteHook
+}
+
+// deleteBatchSize reads DeleteBatchSizeSessionVar, falling back to sql.DefaultDeleteBatchSize if it can't be
+// read (e.g. this build never registered it) or holds a non-positive value.
+func deleteBatchSize(ctx *sql.Context) int {
+	val, err := ctx.GetSessionVariable(ctx, sql.DeleteBatchSizeSessionVar)
+	if err != nil {
+		return sql.DefaultDeleteBatchSize
+	}
+	switch v := val.(type) {
+	case int64:
+		if v > 0 {
+			return int(v)
+		}
+	case int:
+		if v > 0 {
+			return v
+		}
+	}
+	return sql.DefaultDeleteBatchSize
 }
 
 func (d *deleteIter) Next(ctx *sql.Context) (sql.Row, error) {
 	row, err := d.childIter.Next(ctx)
 	if err != nil {
+		if err == io.EOF {
+			if flushErr := d.flushAll(ctx); flushErr != nil {
+				return nil, flushErr
+			}
+		}
 		return nil, err
 	}
 	select {
 	case <-ctx.Done():
+		if flushErr := d.flushAll(ctx); flushErr != nil {
+			return nil, flushErr
+		}
 		return nil, ctx.Err()
 	default:
 	}
@@ -232,16 +561,76 @@ func (d *deleteIter) Next(ctx *sql.Context) (sql.Row, error) {
 	// TODO: handle this in the analyzer instead?
 	fullSchemaLength := len(d.schema)
 	rowLength := len(row)
-	for _, deleter := range d.deleters {
+	for i := range d.deleters {
+		deleter := &d.deleters[i]
+		if !deleter.cascadeBuilt {
+			deleter.cascade, _ = newFKCascadeExecutor(ctx, deleter.db, deleter.table)
+			deleter.cascadeBuilt = true
+		}
+
 		schemaLength := deleter.schemaEnd - deleter.schemaStart
 		subSlice := row
 		if schemaLength < rowLength {
 			subSlice = row[(rowLength - fullSchemaLength + deleter.schemaStart):(rowLength - fullSchemaLength + deleter.schemaEnd)]
 		}
-		err = deleter.deleter.Delete(ctx, subSlice)
-		if err != nil {
-			return nil, err
+		tableName := deleter.table.Name()
+		for _, hook := range d.deleteHooks {
+			if err := hook.BeforeDelete(ctx, tableName, subSlice); err != nil {
+				for _, h := range d.deleteHooks {
+					h.AfterDelete(ctx, tableName, subSlice, err)
+				}
+				return nil, err
+			}
+		}
+		if deleter.hooks != nil {
+			subSlice, err = deleter.hooks.BeforeDelete(ctx, subSlice)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if deleter.cascade != nil {
+			if err := deleter.cascade.run(ctx, subSlice); err != nil {
+				return nil, err
+			}
+		}
+		if deleter.batcher != nil {
+			// The row is only queued here, not yet actually deleted - AfterDelete for it has to wait until
+			// flushDeleteBatch knows whether the batch it ends up in actually succeeded.
+			deleter.buffer = append(deleter.buffer, subSlice)
+			if len(deleter.buffer) >= d.batchSize {
+				if err := d.flushDeleteBatch(ctx, deleter); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		deleteErr := deleter.deleter.Delete(ctx, subSlice)
+		for _, hook := range d.deleteHooks {
+			hook.AfterDelete(ctx, tableName, subSlice, deleteErr)
+		}
+		if deleteErr != nil {
+			return nil, deleteErr
+		}
+		if deleter.hooks != nil {
+			if err := deleter.hooks.AfterDelete(ctx, subSlice); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(d.returning) > 0 {
+		evalRow := row
+		if rowLength > fullSchemaLength {
+			evalRow = row[rowLength-fullSchemaLength:]
+		}
+		returned := make(sql.Row, len(d.returning))
+		for i, e := range d.returning {
+			returned[i], err = e.Eval(ctx, evalRow)
+			if err != nil {
+				return nil, err
+			}
 		}
+		return returned, nil
 	}
 
 	return row, nil
@@ -250,26 +639,97 @@ func (d *deleteIter) Next(ctx *sql.Context) (sql.Row, error) {
 func (d *deleteIter) Close(ctx *sql.Context) error {
 	if !d.closed {
 		d.closed = true
+		var errs []error
+		if err := d.flushAll(ctx); err != nil {
+			errs = append(errs, err)
+		}
 		for _, deleter := range d.deleters {
-			// TODO: collect errs?
 			if err := deleter.deleter.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := d.childIter.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		return aggregateDeleteErrors(errs)
+	}
+	return nil
+}
+
+// flushAll flushes every deleter's pending batch, stopping at (and returning) the first error, so the rest of
+// Close can still run and close the underlying editors.
+func (d *deleteIter) flushAll(ctx *sql.Context) error {
+	for i := range d.deleters {
+		deleter := &d.deleters[i]
+		if len(deleter.buffer) == 0 {
+			continue
+		}
+		if err := d.flushDeleteBatch(ctx, deleter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushDeleteBatch sends deleter's buffered rows to its BatchRowDeleter, clears the buffer (retaining its
+// underlying array capacity for the next batch), and only then fires AfterDelete - for every delete hook in
+// d.deleteHooks and, on success, for deleter.hooks - for each of those rows, passing along the batch's actual
+// outcome. This is what lets an AfterDelete observer (CDC, audit logging) trust that a row it was told about
+// really did get deleted, instead of just queued.
+func (d *deleteIter) flushDeleteBatch(ctx *sql.Context, deleter *deleterStruct) error {
+	rows := deleter.buffer
+	flushErr := deleter.batcher.DeleteBatch(ctx, rows)
+	deleter.buffer = deleter.buffer[:0]
+
+	tableName := deleter.table.Name()
+	for _, row := range rows {
+		for _, hook := range d.deleteHooks {
+			hook.AfterDelete(ctx, tableName, row, flushErr)
+		}
+	}
+	if flushErr != nil {
+		return flushErr
+	}
+	if deleter.hooks != nil {
+		for _, row := range rows {
+			if err := deleter.hooks.AfterDelete(ctx, row); err != nil {
 				return err
 			}
 		}
-		return d.childIter.Close(ctx)
 	}
 	return nil
 }
 
-func newDeleteIter(childIter sql.RowIter, schema sql.Schema, deleters ...deleterStruct) sql.RowIter {
+// aggregateDeleteErrors returns nil if errs is empty, the single error if it holds exactly one, or an error
+// joining every message otherwise, so a Close that hits trouble closing more than one editor doesn't silently
+// report only the last one.
+func aggregateDeleteErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		return fmt.Errorf("multiple errors closing delete editors: %s", strings.Join(msgs, "; "))
+	}
+}
+
+func newDeleteIter(ctx *sql.Context, childIter sql.RowIter, schema sql.Schema, returning []sql.Expression, deleters ...deleterStruct) sql.RowIter {
 	openerClosers := make([]sql.EditOpenerCloser, len(deleters))
 	for i, ds := range deleters {
 		openerClosers[i] = ds.deleter
 	}
 	return NewTableEditorIter(&deleteIter{
-		deleters:  deleters,
-		childIter: childIter,
-		schema:    schema,
+		deleters:    deleters,
+		childIter:   childIter,
+		schema:      schema,
+		returning:   returning,
+		batchSize:   deleteBatchSize(ctx),
+		deleteHooks: ctx.DeleteHooks(),
 	}, openerClosers...)
 }
 
@@ -278,7 +738,7 @@ func (p *DeleteFrom) WithChildren(children ...sql.Node) (sql.Node, error) {
 	if len(children) != 1 {
 		return nil, sql.ErrInvalidChildrenNumber.New(p, len(children), 1)
 	}
-	return NewDeleteFrom(children[0], p.Targets), nil
+	return NewDeleteFrom(children[0], p.Targets).WithReturning(p.Returning), nil
 }
 
 // CheckPrivileges implements the interface sql.Node.
@@ -292,14 +752,26 @@ func (p *DeleteFrom) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedO
 
 func (p DeleteFrom) String() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("Delete")
+	_ = pr.WriteNode(p.nodeLabel())
 	_ = pr.WriteChildren(p.Child.String())
 	return pr.String()
 }
 
 func (p DeleteFrom) DebugString() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("Delete")
+	_ = pr.WriteNode(p.nodeLabel())
 	_ = pr.WriteChildren(sql.DebugString(p.Child))
 	return pr.String()
 }
+
+// nodeLabel returns the String()/DebugString() label for p, including its RETURNING expressions when present.
+func (p DeleteFrom) nodeLabel() string {
+	if len(p.Returning) == 0 {
+		return "Delete"
+	}
+	exprs := make([]string, len(p.Returning))
+	for i, e := range p.Returning {
+		exprs[i] = e.String()
+	}
+	return fmt.Sprintf("Delete(Returning: [%s])", strings.Join(exprs, ", "))
+}
@@ -0,0 +1,180 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// PruneDeleteColumns rewrites a DeleteFrom's child to produce only the columns deleteIter actually needs -
+// the primary key columns of each target (the row identity findPosition/deleteIter.Next needs to call
+// RowDeleter.Delete) plus any column a WHERE filter still above the rewritten subtree references - instead
+// of the full width of every target table. This matters most for a wide table or a multi-target DELETE
+// across a join, where today every unreferenced column gets scanned and carried through the join just to be
+// thrown away in deleteIter.Next.
+//
+// The rewrite is skipped entirely if del has a RETURNING clause (its expressions may reference any column
+// of any target), if the child contains a TriggerExecutor (an AFTER/BEFORE DELETE trigger body may
+// reference any column of OLD), if any target is a HookableTable (BeforeDelete/AfterDelete hooks are
+// documented to receive the row the caller passed to Delete, which must stay the full row for those), or if
+// any target is a sql.ForeignKeyTable with inbound foreign keys (fkCascadeExecutor needs parentRow in the
+// target's own full schema and column order). A target whose table doesn't report primary key ordinals is
+// left unprunable, since there would be no way to know which columns safely identify the row to delete.
+func PruneDeleteColumns(ctx *sql.Context, n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(node sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		del, ok := node.(*DeleteFrom)
+		if !ok {
+			return node, transform.SameTree, nil
+		}
+		newChild, changed := pruneDeleteFromChild(ctx, del)
+		if !changed {
+			return node, transform.SameTree, nil
+		}
+		rewritten, err := del.WithChildren(newChild)
+		if err != nil {
+			return node, transform.SameTree, err
+		}
+		return rewritten, transform.NewTree, nil
+	})
+}
+
+// pruneDeleteFromChild computes the minimal column set del's child needs to produce and, if that's narrower
+// than its current schema, returns a Project over del.Child restricted to it.
+func pruneDeleteFromChild(ctx *sql.Context, del *DeleteFrom) (sql.Node, bool) {
+	if len(del.Returning) > 0 {
+		return nil, false
+	}
+	if containsTriggerExecutor(del.Child) {
+		return nil, false
+	}
+
+	schema := del.Child.Schema()
+	keep := make(map[int]bool)
+
+	targets := del.Targets
+	if len(targets) == 0 {
+		targets = []sql.Node{del.Child}
+	}
+	for _, target := range targets {
+		deletable, err := GetDeletable(target)
+		if err != nil {
+			return nil, false
+		}
+		if _, hasHooks := deletable.(sql.HookableTable); hasHooks {
+			return nil, false
+		}
+		if fkTable, isFkTable := deletable.(sql.ForeignKeyTable); isFkTable {
+			if fks, err := fkTable.GetReferencedForeignKeys(ctx); err != nil || len(fks) > 0 {
+				return nil, false
+			}
+		}
+		start, end, ok := tryFindPosition(schema, deletable.Name())
+		if !ok {
+			return nil, false
+		}
+		pkt, ok := deletable.(sql.PrimaryKeyTable)
+		if !ok {
+			// No primary key ordinals to prune down to - keep this target's whole column range.
+			for i := start; i < end; i++ {
+				keep[i] = true
+			}
+			continue
+		}
+		for _, ord := range pkt.PrimaryKeySchema().PkOrdinals {
+			keep[start+ord] = true
+		}
+	}
+
+	for _, idx := range collectFilterReferencedIndices(del.Child) {
+		keep[idx] = true
+	}
+
+	if len(keep) >= len(schema) {
+		return nil, false
+	}
+
+	indices := make([]int, 0, len(keep))
+	for i := range keep {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	projections := make([]sql.Expression, len(indices))
+	for i, idx := range indices {
+		c := schema[idx]
+		projections[i] = expression.NewGetField(idx, c.Type, c.Name, c.Nullable)
+	}
+	return NewProject(projections, del.Child), true
+}
+
+// containsTriggerExecutor reports whether n or any of its descendants is a TriggerExecutor.
+func containsTriggerExecutor(n sql.Node) bool {
+	if _, ok := n.(*TriggerExecutor); ok {
+		return true
+	}
+	for _, c := range n.Children() {
+		if containsTriggerExecutor(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectFilterReferencedIndices returns the schema indices referenced by every Filter node in n's subtree.
+func collectFilterReferencedIndices(n sql.Node) []int {
+	var indices []int
+	if f, ok := n.(*Filter); ok {
+		indices = append(indices, collectExpressionIndices(f.Expression)...)
+	}
+	for _, c := range n.Children() {
+		indices = append(indices, collectFilterReferencedIndices(c)...)
+	}
+	return indices
+}
+
+func collectExpressionIndices(e sql.Expression) []int {
+	var indices []int
+	if gf, ok := e.(*expression.GetField); ok {
+		indices = append(indices, gf.Index())
+	}
+	for _, c := range e.Children() {
+		indices = append(indices, collectExpressionIndices(c)...)
+	}
+	return indices
+}
+
+// tryFindPosition is findPosition without the panic: it reports ok=false instead of panicking when name
+// isn't found in schema, so a caller can fall back safely.
+func tryFindPosition(schema sql.Schema, name string) (start, end int, ok bool) {
+	foundStart := false
+	for i, col := range schema {
+		if col.Source == name {
+			if !foundStart {
+				start = i
+				foundStart = true
+			}
+		} else if foundStart {
+			return start, i, true
+		}
+	}
+	if foundStart {
+		return start, len(schema), true
+	}
+	return 0, 0, false
+}
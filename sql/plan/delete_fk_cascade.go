@@ -0,0 +1,234 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// ErrForeignKeyChildViolation is returned when a row can't be deleted (or have its referenced columns
+// changed) because another table still holds a row referencing it through a RESTRICT or NO ACTION foreign
+// key - the DELETE equivalent of MySQL's ER_ROW_IS_REFERENCED_2.
+var ErrForeignKeyChildViolation = errors.NewKind("cannot delete or update a parent row: a foreign key constraint %q on table %q fails (referenced by %q)")
+
+// fkCascadeExecutor enforces the ON DELETE action declared by every foreign key that references a DELETE
+// target, the first time it's asked to run for a given parent row. It's built lazily by deleteIter.Next,
+// once per target, from the sql.Database the target itself was resolved from - the same one deleteDatabaseHelper
+// already knows how to find - so it can look up each referencing child table by name.
+type fkCascadeExecutor struct {
+	db     sql.Database
+	parent sql.ForeignKeyTable
+	fks    []sql.ForeignKeyConstraint
+}
+
+// newFKCascadeExecutor builds an fkCascadeExecutor for deletable, or returns ok=false if deletable declares
+// no inbound foreign keys (nothing to enforce) or isn't a sql.ForeignKeyTable at all.
+func newFKCascadeExecutor(ctx *sql.Context, db sql.Database, deletable sql.DeletableTable) (*fkCascadeExecutor, bool) {
+	fkTable, ok := deletable.(sql.ForeignKeyTable)
+	if !ok || db == nil {
+		return nil, false
+	}
+	fks, err := fkTable.GetReferencedForeignKeys(ctx)
+	if err != nil || len(fks) == 0 {
+		return nil, false
+	}
+	return &fkCascadeExecutor{db: db, parent: fkTable, fks: fks}, true
+}
+
+// run enforces every fk in e against parentRow, a row in the parent table's own schema (the same width and
+// column order as e.parent.Schema()) that's about to be deleted.
+func (e *fkCascadeExecutor) run(ctx *sql.Context, parentRow sql.Row) error {
+	parentSchema := e.parent.Schema()
+	for _, fk := range e.fks {
+		if err := e.enforce(ctx, fk, parentSchema, parentRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *fkCascadeExecutor) enforce(ctx *sql.Context, fk sql.ForeignKeyConstraint, parentSchema sql.Schema, parentRow sql.Row) error {
+	parentIdxs, ok := schemaColumnIndexes(parentSchema, fk.ParentColumns)
+	if !ok {
+		return nil
+	}
+	parentKey := make(sql.Row, len(parentIdxs))
+	for i, idx := range parentIdxs {
+		parentKey[i] = parentRow[idx]
+	}
+
+	childTable, ok, err := e.db.GetTableInsensitive(ctx, fk.Table)
+	if err != nil || !ok {
+		return nil
+	}
+	childSchema := childTable.Schema()
+	childIdxs, ok := schemaColumnIndexes(childSchema, fk.Columns)
+	if !ok {
+		return nil
+	}
+
+	var matches []sql.Row
+	err = sql.IterRecordsFromPartitions(ctx, childTable, func(_ int64, row sql.Row) (bool, error) {
+		matched, err := fkRowMatches(childSchema, row, childIdxs, parentKey)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			matches = append(matches, row)
+		}
+		return true, nil
+	})
+	if err != nil || len(matches) == 0 {
+		return err
+	}
+
+	switch fk.OnDelete {
+	case sql.ForeignKeyReferentialAction_Cascade:
+		deletable, ok := childTable.(sql.DeletableTable)
+		if !ok {
+			return ErrForeignKeyChildViolation.New(fk.Name, e.parent.Name(), fk.Table)
+		}
+		// Each matched row is itself about to be deleted from the child table, so before deleting it, enforce
+		// whatever foreign keys reference *that* table in turn - this is what makes a multi-level cascade chain
+		// (grandchild rows and beyond) actually reach the bottom instead of stopping one level down.
+		childCascade, _ := newFKCascadeExecutor(ctx, e.db, deletable)
+		deleter := deletable.Deleter(ctx)
+		for _, row := range matches {
+			if childCascade != nil {
+				if err := childCascade.run(ctx, row); err != nil {
+					deleter.Close(ctx)
+					return err
+				}
+			}
+			if err := deleter.Delete(ctx, row); err != nil {
+				deleter.Close(ctx)
+				return err
+			}
+		}
+		return deleter.Close(ctx)
+	case sql.ForeignKeyReferentialAction_SetNull, sql.ForeignKeyReferentialAction_SetDefault:
+		updatable, ok := childTable.(sql.UpdatableTable)
+		if !ok {
+			return ErrForeignKeyChildViolation.New(fk.Name, e.parent.Name(), fk.Table)
+		}
+		updater := updatable.Updater(ctx)
+		for _, row := range matches {
+			newRow := append(sql.Row{}, row...)
+			for _, idx := range childIdxs {
+				if fk.OnDelete == sql.ForeignKeyReferentialAction_SetNull || childSchema[idx].Default == nil {
+					newRow[idx] = nil
+					continue
+				}
+				def, err := childSchema[idx].Default.Eval(ctx, newRow)
+				if err != nil {
+					updater.Close(ctx)
+					return err
+				}
+				newRow[idx] = def
+			}
+			if err := updater.Update(ctx, row, newRow); err != nil {
+				updater.Close(ctx)
+				return err
+			}
+		}
+		return updater.Close(ctx)
+	default:
+		// DefaultAction, NoAction, and Restrict all require the referencing rows to be gone already.
+		return ErrForeignKeyChildViolation.New(fk.Name, e.parent.Name(), fk.Table)
+	}
+}
+
+// foreignKeyHasReferencingRows reports whether any row in fk's child table still references the parent table -
+// i.e. has every one of fk.Columns set to a non-null value. Foreign key integrity guarantees such a row
+// corresponds to an existing parent row, so truncateTarget/rangeDeleteTarget use this to refuse their bulk
+// fast path for a RESTRICT/NO ACTION/default foreign key whenever it would actually orphan a child row,
+// without needing to resolve which specific parent row each child row references. Returns true (safest
+// answer) if the child table or its columns can't be resolved, since that means the check can't be trusted.
+func foreignKeyHasReferencingRows(ctx *sql.Context, db sql.Database, fk sql.ForeignKeyConstraint) (bool, error) {
+	if db == nil {
+		return true, nil
+	}
+	childTable, ok, err := db.GetTableInsensitive(ctx, fk.Table)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return true, nil
+	}
+	childIdxs, ok := schemaColumnIndexes(childTable.Schema(), fk.Columns)
+	if !ok {
+		return true, nil
+	}
+
+	referenced := false
+	err = sql.IterRecordsFromPartitions(ctx, childTable, func(_ int64, row sql.Row) (bool, error) {
+		for _, idx := range childIdxs {
+			if idx >= len(row) || row[idx] == nil {
+				return true, nil
+			}
+		}
+		referenced = true
+		return false, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return referenced, nil
+}
+
+// schemaColumnIndexes resolves each of names to its index in schema, in the same order, or reports ok=false
+// if any name isn't found - which leaves the caller free to skip an unresolvable constraint rather than fail
+// the whole delete over it.
+func schemaColumnIndexes(schema sql.Schema, names []string) ([]int, bool) {
+	idxs := make([]int, len(names))
+	for i, name := range names {
+		found := false
+		for j, col := range schema {
+			if col.Name == name {
+				idxs[i] = j
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return idxs, true
+}
+
+// fkRowMatches reports whether row's values at cols equal key, in order, comparing each column through its
+// own sql.Type.Compare rather than Go's == on the raw values - the same way the rest of the engine compares
+// column values, so a numeric value stored in one Go representation still matches another (e.g. int64 vs
+// decimal.Decimal) and a collation-insensitive string type still matches case-insensitively. A row with a
+// NULL in any of cols never matches: MySQL's default MATCH SIMPLE never treats such a row as referencing any
+// parent row, the same nil check foreignKeyHasReferencingRows already applies.
+func fkRowMatches(schema sql.Schema, row sql.Row, cols []int, key sql.Row) (bool, error) {
+	for i, idx := range cols {
+		if idx >= len(row) || row[idx] == nil {
+			return false, nil
+		}
+		cmp, err := schema[idx].Type.Compare(row[idx], key[i])
+		if err != nil {
+			return false, err
+		}
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// FuseAdjacentFilters merges directly stacked Filter nodes - the kind predicate pushdown often leaves behind,
+// one for the predicates it pushed down to each level it passed through - into a single Filter ANDing all of
+// their conjuncts together, then simplifies the fused conjunction: a conjunct that constant-folds to TRUE is
+// dropped as redundant, and a conjunct that folds to FALSE or NULL short-circuits the whole subtree to an
+// EmptyTable, since no row can ever satisfy it. Run this after pushdown so it sees the filters pushdown
+// actually produced.
+//
+// A conjunct referencing a subquery is left untouched rather than evaluated - subqueries are expensive and
+// potentially correlated, and constant-folding is only ever a cheap local simplification, not a license to run
+// arbitrary plans during analysis.
+func FuseAdjacentFilters(ctx *sql.Context, n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(node sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		f, ok := node.(*Filter)
+		if !ok {
+			return node, transform.SameTree, nil
+		}
+
+		conjuncts := expression.SplitConjunction(f.Expression)
+		child := f.Child
+		fusedChild := false
+		for {
+			childFilter, ok := child.(*Filter)
+			if !ok {
+				break
+			}
+			conjuncts = append(conjuncts, expression.SplitConjunction(childFilter.Expression)...)
+			child = childFilter.Child
+			fusedChild = true
+		}
+
+		simplified, isFalse, err := simplifyConjuncts(ctx, conjuncts)
+		if err != nil {
+			return node, transform.SameTree, err
+		}
+		if isFalse {
+			return NewEmptyTableWithSchema(child.Schema()), transform.NewTree, nil
+		}
+
+		if !fusedChild && len(simplified) == len(conjuncts) {
+			// Nothing to fuse and nothing dropped - leave the node as-is.
+			return node, transform.SameTree, nil
+		}
+		if len(simplified) == 0 {
+			return child, transform.NewTree, nil
+		}
+		return NewFilter(expression.JoinAnd(simplified...), child), transform.NewTree, nil
+	})
+}
+
+// simplifyConjuncts drops every conjunct that constant-folds to TRUE. If any conjunct folds to FALSE or NULL,
+// isFalse is true and the returned slice should be ignored - no row can satisfy the fused filter.
+func simplifyConjuncts(ctx *sql.Context, conjuncts []sql.Expression) (kept []sql.Expression, isFalse bool, err error) {
+	for _, c := range conjuncts {
+		if containsSubquery(c) || !isConstantExpression(c) {
+			kept = append(kept, c)
+			continue
+		}
+		val, err := c.Eval(ctx, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		b, ok := val.(bool)
+		if !ok {
+			// Non-boolean constants (e.g. a NULL from a comparison against NULL) are treated as FALSE,
+			// same as MySQL's WHERE clause semantics.
+			return nil, true, nil
+		}
+		if !b {
+			return nil, true, nil
+		}
+		// b == true: drop this tautological conjunct.
+	}
+	return kept, false, nil
+}
+
+// containsSubquery reports whether e or any of its children is a subquery expression, correlated or not.
+func containsSubquery(e sql.Expression) bool {
+	if _, ok := e.(*Subquery); ok {
+		return true
+	}
+	for _, c := range e.Children() {
+		if containsSubquery(c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// NullabilityOverride wraps a child node and reports a subset of its output columns as Nullable, without
+// otherwise changing the rows the child produces. ResolveOuterJoinNullability inserts one directly above
+// each JoinNode whose join type null-produces rows on one side (LEFT/RIGHT/FULL OUTER JOIN), so a caller
+// reading the returned schema sees accurate nullability for the outer-joined columns - the declared schema
+// of the underlying table alone can't express that a LEFT JOIN may substitute NULLs for its right side.
+type NullabilityOverride struct {
+	UnaryNode
+	// ForcedNullable holds the Child.Schema() indexes that must report Nullable = true.
+	ForcedNullable map[int]bool
+}
+
+var _ sql.Node = (*NullabilityOverride)(nil)
+
+// NewNullabilityOverride creates a NullabilityOverride wrapping child, forcing Nullable = true on each column
+// index named in forcedNullable.
+func NewNullabilityOverride(child sql.Node, forcedNullable map[int]bool) *NullabilityOverride {
+	return &NullabilityOverride{
+		UnaryNode:      UnaryNode{child},
+		ForcedNullable: forcedNullable,
+	}
+}
+
+// Schema implements the sql.Node interface.
+func (n *NullabilityOverride) Schema() sql.Schema {
+	childSchema := n.Child.Schema()
+	if len(n.ForcedNullable) == 0 {
+		return childSchema
+	}
+	schema := make(sql.Schema, len(childSchema))
+	for i, c := range childSchema {
+		if !n.ForcedNullable[i] || c.Nullable {
+			schema[i] = c
+			continue
+		}
+		cp := *c
+		cp.Nullable = true
+		schema[i] = &cp
+	}
+	return schema
+}
+
+// RowIter implements the sql.Node interface. This node is schema-only - it never changes the rows its child
+// produces, only how their columns are reported as nullable.
+func (n *NullabilityOverride) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	return n.Child.RowIter(ctx, row)
+}
+
+// WithChildren implements the sql.Node interface.
+func (n *NullabilityOverride) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 1)
+	}
+	return NewNullabilityOverride(children[0], n.ForcedNullable), nil
+}
+
+func (n *NullabilityOverride) String() string {
+	return n.Child.String()
+}
+
+func (n *NullabilityOverride) DebugString() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("NullabilityOverride")
+	_ = pr.WriteChildren(sql.DebugString(n.Child))
+	return pr.String()
+}
+
+// ResolveOuterJoinNullability walks n bottom-up and, for each JoinNode whose Op is a LEFT, RIGHT, or FULL
+// OUTER join, wraps the JoinNode itself in a NullabilityOverride marking the null-producing side's columns
+// of the join's schema Nullable. The override sits directly above the join, so it's picked up automatically
+// as that schema
+// propagates up through Project, Filter, set ops, and subquery/CTE boundaries the same way any other schema
+// change would. A WHERE filter elsewhere in the tree that happens to convert a LEFT JOIN back into
+// inner-join semantics (e.g. `uv.v IS NOT NULL`) is not special-cased here - the override reflects what the
+// join itself can produce, matching how MySQL and PostgreSQL report column nullability from their wire
+// protocols.
+func ResolveOuterJoinNullability(ctx *sql.Context, n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(node sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		j, ok := node.(*JoinNode)
+		if !ok {
+			return node, transform.SameTree, nil
+		}
+
+		leftLen := len(j.Left().Schema())
+		var forced map[int]bool
+		changed := false
+
+		if j.Op.IsLeftOuter() || j.Op.IsFullOuter() {
+			// The right side may be all-NULL when no match is found.
+			forced = forceNullableRange(forced, leftLen, leftLen+len(j.Right().Schema()))
+			changed = true
+		}
+		if j.Op.IsRightOuter() || j.Op.IsFullOuter() {
+			// The left side may be all-NULL when no match is found.
+			forced = forceNullableRange(forced, 0, leftLen)
+			changed = true
+		}
+		if !changed {
+			return node, transform.SameTree, nil
+		}
+		return NewNullabilityOverride(node, forced), transform.NewTree, nil
+	})
+}
+
+func forceNullableRange(forced map[int]bool, start, end int) map[int]bool {
+	if forced == nil {
+		forced = make(map[int]bool, end-start)
+	}
+	for i := start; i < end; i++ {
+		forced[i] = true
+	}
+	return forced
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+// comparisonExpression is implemented by the binary comparison expressions (=, <, <=, >, >=) that
+// sql/expression's comparison constructors build. MatchExpressionIndex uses it to pull apart a filter
+// without depending on every concrete comparison type.
+type comparisonExpression interface {
+	sql.Expression
+	Left() sql.Expression
+	Right() sql.Expression
+}
+
+// MatchExpressionIndex looks for an sql.ExpressionIndex on table's underlying table whose IndexedExpressions
+// contains an expression structurally identical to filter's left-hand side - e.g. filter is
+// `json_extract(j, '$.a') = 5` and the index was built over `json_extract(j, '$.a')` - after normalizing
+// both sides and folding filter's right-hand side down to a literal. It returns the matching index and the
+// literal value to look up, or ok=false if filter isn't eligible for an index rewrite (e.g. the index
+// catalog has nothing to offer, or filter's left side doesn't match any IndexedExpressions entry
+// structurally). The caller is responsible for building the sql.IndexLookup and swapping in an
+// IndexedTableAccess over table.Underlying() once MatchExpressionIndex reports a hit.
+func MatchExpressionIndex(ctx *sql.Context, table *VirtualColumnTable, filter sql.Expression) (idx sql.ExpressionIndex, key interface{}, ok bool, err error) {
+	cmp, isCmp := filter.(comparisonExpression)
+	if !isCmp {
+		return nil, nil, false, nil
+	}
+
+	left := normalizeIndexExpr(cmp.Left())
+	if left == "" {
+		return nil, nil, false, nil
+	}
+
+	right := cmp.Right()
+	if !isConstantExpression(right) {
+		return nil, nil, false, nil
+	}
+	key, err = right.Eval(ctx, nil)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	indexable, isIndexable := table.Table.(sql.IndexAddressable)
+	if !isIndexable {
+		return nil, nil, false, nil
+	}
+	indexes, err := indexable.GetIndexes(ctx)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	for _, candidate := range indexes {
+		exprIdx, isExprIdx := candidate.(sql.ExpressionIndex)
+		if !isExprIdx {
+			continue
+		}
+		for _, indexedExpr := range exprIdx.IndexedExpressions() {
+			if normalizeIndexExpr(indexedExpr) == left {
+				return exprIdx, key, true, nil
+			}
+		}
+	}
+	return nil, nil, false, nil
+}
+
+// normalizeIndexExpr returns a canonical string form of expr for structural comparison: lower-cased, with
+// redundant parentheses and alias wrappers stripped by expression.String's own formatting. Two expressions
+// built from the same SQL text - one freshly parsed out of a WHERE clause, the other recorded when an index
+// was created - normalize to the same string even if they were parsed on different occasions.
+func normalizeIndexExpr(expr sql.Expression) string {
+	for {
+		if aliased, isAlias := expr.(*expression.Alias); isAlias {
+			expr = aliased.Child
+			continue
+		}
+		break
+	}
+	return strings.ToLower(expr.String())
+}
+
+// isConstantExpression reports whether expr can be evaluated without a row, i.e. it contains no column
+// references, so it can stand in for the literal side of an indexed comparison.
+func isConstantExpression(expr sql.Expression) bool {
+	if _, isGetField := expr.(*expression.GetField); isGetField {
+		return false
+	}
+	for _, child := range expr.Children() {
+		if !isConstantExpression(child) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// TestNormalizeIndexExprStripsAliasesAndCase confirms two structurally equal expressions - one wrapped in an
+// alias, one not, differing only in case - normalize to the same string, which is what lets
+// MatchExpressionIndex compare a freshly parsed WHERE clause against an index recorded on a separate
+// occasion.
+func TestNormalizeIndexExprStripsAliasesAndCase(t *testing.T) {
+	plain := expression.NewGetField(0, types.Int64, "A", false)
+	aliased := expression.NewAlias("alias_name", expression.NewGetField(0, types.Int64, "a", false))
+
+	if got, want := normalizeIndexExpr(aliased), normalizeIndexExpr(plain); got != want {
+		t.Fatalf("expected normalized forms to match, got %q and %q", got, want)
+	}
+}
+
+// TestIsConstantExpressionRejectsColumnReferences confirms a literal is constant and a GetField - directly,
+// or nested inside another expression - is not, since MatchExpressionIndex only treats a comparison's
+// right-hand side as an index lookup key when it can be evaluated without a row.
+func TestIsConstantExpressionRejectsColumnReferences(t *testing.T) {
+	literal := expression.NewLiteral(int64(5), types.Int64)
+	if !isConstantExpression(literal) {
+		t.Fatalf("expected a literal to be constant")
+	}
+
+	column := expression.NewGetField(0, types.Int64, "a", false)
+	if isConstantExpression(column) {
+		t.Fatalf("expected a column reference to not be constant")
+	}
+
+	wrapped := expression.NewAlias("x", column)
+	if isConstantExpression(wrapped) {
+		t.Fatalf("expected an expression nesting a column reference to not be constant")
+	}
+}
+
+// TestMatchExpressionIndexRejectsIneligibleFilters confirms MatchExpressionIndex reports ok=false, rather
+// than erroring or panicking, for each of filter's disqualifying shapes: not a comparison, a non-constant
+// right-hand side, and an underlying table that isn't sql.IndexAddressable at all. This snapshot declares no
+// sql.Index implementation anywhere, so the matching hit path (an actual index lookup) can't be exercised
+// end-to-end here; these cover every short-circuit MatchExpressionIndex takes before it would reach one.
+func TestMatchExpressionIndexRejectsIneligibleFilters(t *testing.T) {
+	v, _ := virtualColumnPushdownTestTable()
+	ctx := sql.NewEmptyContext()
+
+	notAComparison := expression.NewGetField(0, types.Int64, "a", false)
+	if _, _, ok, err := MatchExpressionIndex(ctx, v, notAComparison); err != nil || ok {
+		t.Fatalf("expected ok=false for a non-comparison filter, got ok=%v err=%v", ok, err)
+	}
+
+	nonConstantRHS := expression.NewEquals(
+		expression.NewGetField(0, types.Int64, "a", false),
+		expression.NewGetField(1, types.Int64, "b", false),
+	)
+	if _, _, ok, err := MatchExpressionIndex(ctx, v, nonConstantRHS); err != nil || ok {
+		t.Fatalf("expected ok=false for a non-constant right-hand side, got ok=%v err=%v", ok, err)
+	}
+
+	// v's underlying table (a projectedTable, built on insertRecordingTable) doesn't implement
+	// sql.IndexAddressable at all.
+	eligibleShape := expression.NewEquals(
+		expression.NewGetField(0, types.Int64, "a", false),
+		expression.NewLiteral(int64(1), types.Int64),
+	)
+	if _, _, ok, err := MatchExpressionIndex(ctx, v, eligibleShape); err != nil || ok {
+		t.Fatalf("expected ok=false when the underlying table isn't IndexAddressable, got ok=%v err=%v", ok, err)
+	}
+}
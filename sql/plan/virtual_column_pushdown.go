@@ -0,0 +1,197 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+// SplitVirtualColumnFilter splits filter - a predicate sitting above a VirtualColumnTable, normalized to a
+// list of AND-ed conjuncts - into the conjuncts that can be pushed down into v.Underlying() and the
+// conjuncts that must stay above the wrapper. A conjunct that only references v's base columns is pushable
+// as-is; a conjunct that references one of v's virtual projections is pushable only after inlining that
+// projection's expression produces something that in turn only references base columns (e.g. `vcol = 5`
+// where vcol's projection is `a+1` inlines to `a+1 = 5`, which a constant-folding rule downstream can reduce
+// to `a = 4`). Everything else - conjuncts that can't be reduced to base columns at all - is returned in
+// remainder. The caller (an analyzer pushdown rule) is responsible for actually calling
+// v.Underlying().(sql.FilteredTable).WithFilters with pushable.
+func SplitVirtualColumnFilter(v *VirtualColumnTable, filter sql.Expression) (pushable []sql.Expression, remainder []sql.Expression) {
+	for _, conjunct := range splitConjunction(filter) {
+		if !referencesVirtualColumn(v, conjunct) {
+			pushable = append(pushable, conjunct)
+			continue
+		}
+		if inlined, ok := inlineVirtualColumns(v, conjunct); ok && !referencesVirtualColumn(v, inlined) {
+			pushable = append(pushable, inlined)
+			continue
+		}
+		remainder = append(remainder, conjunct)
+	}
+	return pushable, remainder
+}
+
+// splitConjunction flattens a tree of *expression.And nodes into its leaf conjuncts. A filter that isn't an
+// And at all is returned as its own single-element list.
+func splitConjunction(filter sql.Expression) []sql.Expression {
+	and, ok := filter.(*expression.And)
+	if !ok {
+		return []sql.Expression{filter}
+	}
+	return append(splitConjunction(and.Left), splitConjunction(and.Right)...)
+}
+
+// referencesVirtualColumn reports whether expr references any column name that v.Projections provides a
+// non-trivial expression for - i.e. a generated (virtual or stored) column rather than a plain passthrough
+// of the underlying table's own column.
+func referencesVirtualColumn(v *VirtualColumnTable, expr sql.Expression) bool {
+	for _, name := range referencedColumns(expr) {
+		if isVirtualProjection(v, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVirtualProjection reports whether name is produced by one of v's Projections entries rather than being
+// a plain passthrough GetField onto the identically named column in v.Underlying()'s schema.
+func isVirtualProjection(v *VirtualColumnTable, name string) bool {
+	schema := v.Table.Schema()
+	for i, col := range schema {
+		if col.Name != name {
+			continue
+		}
+		if i >= len(v.Projections) {
+			return false
+		}
+		gf, isPlainGetField := v.Projections[i].(*expression.GetField)
+		return !(isPlainGetField && gf.Name() == col.Name)
+	}
+	// name isn't one of the underlying table's columns at all - e.g. it's an alias introduced above this
+	// node - so there's nothing for this wrapper to inline.
+	return false
+}
+
+// inlineVirtualColumns returns a copy of expr with every GetField reference to one of v's virtual
+// projections replaced by that projection's underlying expression, and ok=true if at least one substitution
+// was made.
+func inlineVirtualColumns(v *VirtualColumnTable, expr sql.Expression) (sql.Expression, bool) {
+	replaced := false
+	schema := v.Table.Schema()
+
+	var rewrite func(e sql.Expression) sql.Expression
+	rewrite = func(e sql.Expression) sql.Expression {
+		if gf, ok := e.(*expression.GetField); ok {
+			for i, col := range schema {
+				if col.Name != gf.Name() || i >= len(v.Projections) {
+					continue
+				}
+				if plain, isPlainGetField := v.Projections[i].(*expression.GetField); isPlainGetField && plain.Name() == col.Name {
+					return e
+				}
+				replaced = true
+				return v.Projections[i]
+			}
+			return e
+		}
+		children := e.Children()
+		if len(children) == 0 {
+			return e
+		}
+		newChildren := make([]sql.Expression, len(children))
+		changed := false
+		for i, c := range children {
+			newChildren[i] = rewrite(c)
+			if newChildren[i] != c {
+				changed = true
+			}
+		}
+		if !changed {
+			return e
+		}
+		newExpr, err := e.WithChildren(newChildren...)
+		if err != nil {
+			return e
+		}
+		return newExpr
+	}
+
+	result := rewrite(expr)
+	return result, replaced
+}
+
+// referencedColumns returns the distinct column names expr's GetField leaves reference.
+func referencedColumns(expr sql.Expression) []string {
+	var names []string
+	if gf, ok := expr.(*expression.GetField); ok {
+		return []string{gf.Name()}
+	}
+	for _, child := range expr.Children() {
+		names = append(names, referencedColumns(child)...)
+	}
+	return names
+}
+
+// PruneVirtualColumnProjections returns a copy of v whose Projections (and Generated entries) are narrowed
+// to keep, together with the v.Underlying() table narrowed - via ProjectedTable.WithProjections, when the
+// underlying table implements it - to just the base columns that the surviving projections (plus keep
+// itself, for any kept column with no projection override) actually read. A column pruning rule calls this
+// once it has determined keep from the columns a Project node above v actually outputs.
+func PruneVirtualColumnProjections(v *VirtualColumnTable, keep []string) *VirtualColumnTable {
+	keepSet := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		keepSet[name] = true
+	}
+
+	schema := v.Table.Schema()
+	newProjections := make([]sql.Expression, 0, len(keep))
+	baseCols := make(map[string]bool)
+	var newGenerated map[string]sql.GeneratedColumnDetails
+	if v.Generated != nil {
+		newGenerated = make(map[string]sql.GeneratedColumnDetails)
+	}
+
+	for i, col := range schema {
+		if !keepSet[col.Name] {
+			continue
+		}
+		if i < len(v.Projections) {
+			newProjections = append(newProjections, v.Projections[i])
+			for _, ref := range referencedColumns(v.Projections[i]) {
+				baseCols[ref] = true
+			}
+		} else {
+			baseCols[col.Name] = true
+		}
+		if gc, ok := v.Generated[col.Name]; ok {
+			newGenerated[col.Name] = gc
+		}
+	}
+
+	underlying := v.Table
+	if projectable, ok := underlying.(sql.ProjectedTable); ok {
+		baseColNames := make([]string, 0, len(baseCols))
+		for name := range baseCols {
+			baseColNames = append(baseColNames, name)
+		}
+		underlying = projectable.WithProjections(baseColNames)
+	}
+
+	return &VirtualColumnTable{
+		Table:       underlying,
+		Projections: newProjections,
+		Generated:   newGenerated,
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// projectedTable is a minimal sql.ProjectedTable test double that records the column names it was last
+// asked to project, so PruneVirtualColumnProjections's narrowing of the underlying table can be observed.
+type projectedTable struct {
+	insertRecordingTable
+	lastProjection []string
+}
+
+var _ sql.ProjectedTable = (*projectedTable)(nil)
+
+func (t *projectedTable) WithProjections(colNames []string) sql.Table {
+	t.lastProjection = colNames
+	return t
+}
+
+func (t *projectedTable) Projections() []string { return t.lastProjection }
+
+func virtualColumnPushdownTestTable() (*VirtualColumnTable, *projectedTable) {
+	schema := sql.Schema{
+		{Name: "a", Type: types.Int64},
+		{Name: "b", Type: types.Int64},
+		{Name: "vcol", Type: types.Int64},
+	}
+	underlying := &projectedTable{insertRecordingTable: insertRecordingTable{name: "t", schema: schema}}
+	projections := []sql.Expression{
+		expression.NewGetField(0, types.Int64, "a", false),
+		expression.NewGetField(1, types.Int64, "b", false),
+		// vcol's projection is a+1, not a plain passthrough, so it's the virtual column under test.
+		&storedColumnExpr{col: 0},
+	}
+	v := NewVirtualColumnTable(underlying, projections)
+	return v, underlying
+}
+
+// TestSplitVirtualColumnFilterPushesBaseColumnConjunctsDown confirms a conjunct referencing only base
+// columns is pushable as-is, while one referencing the virtual column is pushed only in inlined form, with
+// vcol replaced by its projection, since that projection reads no other virtual column.
+func TestSplitVirtualColumnFilterPushesBaseColumnConjunctsDown(t *testing.T) {
+	v, _ := virtualColumnPushdownTestTable()
+
+	pushableConjunct := expression.NewEquals(
+		expression.NewGetField(0, types.Int64, "a", false),
+		expression.NewLiteral(int64(1), types.Int64),
+	)
+	virtualConjunct := expression.NewEquals(
+		expression.NewGetField(2, types.Int64, "vcol", false),
+		expression.NewLiteral(int64(5), types.Int64),
+	)
+	filter := expression.NewAnd(pushableConjunct, virtualConjunct)
+
+	pushable, remainder := SplitVirtualColumnFilter(v, filter)
+
+	if len(pushable) != 2 || pushable[0].String() != pushableConjunct.String() {
+		t.Fatalf("expected the base-column conjunct and the inlined virtual-column conjunct to be pushable, got %v", pushable)
+	}
+	if pushable[1].String() == virtualConjunct.String() {
+		t.Fatalf("expected the virtual-column conjunct to be pushed with vcol inlined, got %v", pushable[1])
+	}
+	if len(remainder) != 0 {
+		t.Fatalf("expected no conjunct to remain, got %v", remainder)
+	}
+}
+
+// TestPruneVirtualColumnProjectionsNarrowsUnderlyingTable confirms dropping an unused virtual column also
+// narrows the underlying ProjectedTable down to just the base columns the surviving projections still read.
+func TestPruneVirtualColumnProjectionsNarrowsUnderlyingTable(t *testing.T) {
+	v, underlying := virtualColumnPushdownTestTable()
+
+	pruned := PruneVirtualColumnProjections(v, []string{"a", "b"})
+
+	if len(pruned.Projections) != 2 {
+		t.Fatalf("expected 2 surviving projections, got %d", len(pruned.Projections))
+	}
+	prunedUnderlying, ok := pruned.Table.(*projectedTable)
+	if !ok {
+		t.Fatalf("expected pruned table to still be a *projectedTable, got %T", pruned.Table)
+	}
+	if prunedUnderlying != underlying {
+		t.Fatalf("expected WithProjections to be called on the same underlying table instance")
+	}
+	if len(underlying.lastProjection) != 2 {
+		t.Fatalf("expected underlying table to be narrowed to 2 base columns, got %v", underlying.lastProjection)
+	}
+}
@@ -21,15 +21,26 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 )
 
-// VirtualColumnTable is a sql.TableNode that combines a ResolvedTable with a Project, the latter of which is used 
+// VirtualColumnTable is a sql.TableNode that combines a ResolvedTable with a Project, the latter of which is used
 // to add the values of virtual columns to the table.
+//
+// Projections holds one expression per column in the underlying table's schema, evaluated on every read to
+// fill in that column's value. A column generated with GENERATED ALWAYS AS (...) VIRTUAL belongs there, since
+// it is recomputed on every access and never touches disk. A column generated with the STORED keyword instead
+// has its expression recorded in Generated: it is evaluated once, on write, by the RowInserter/RowUpdater/
+// RowReplacer this table hands back, and its Projections entry is left as a plain GetField so a read never
+// re-evaluates it.
 type VirtualColumnTable struct {
 	sql.Table
 	Projections []sql.Expression
+	Generated   map[string]sql.GeneratedColumnDetails
 }
 
 var _ sql.TableWrapper = (*VirtualColumnTable)(nil)
 var _ sql.MutableTableWrapper = (*VirtualColumnTable)(nil)
+var _ sql.InsertableTable = (*VirtualColumnTable)(nil)
+var _ sql.UpdatableTable = (*VirtualColumnTable)(nil)
+var _ sql.ReplaceableTable = (*VirtualColumnTable)(nil)
 
 func (v *VirtualColumnTable) Underlying() sql.Table {
 	return v.Table
@@ -40,7 +51,7 @@ func (v VirtualColumnTable) WithUnderlying(table sql.Table) sql.Table {
 	return &v
 }
 
-// NewVirtualColumnTable creates a new VirtualColumnTable.
+// NewVirtualColumnTable creates a new VirtualColumnTable with no STORED generated columns.
 func NewVirtualColumnTable(table sql.Table, projections []sql.Expression) *VirtualColumnTable {
 	return &VirtualColumnTable{
 		Table:       table,
@@ -48,6 +59,33 @@ func NewVirtualColumnTable(table sql.Table, projections []sql.Expression) *Virtu
 	}
 }
 
+// NewVirtualColumnTableWithGenerated creates a new VirtualColumnTable that also tracks the STORED generated
+// columns named in generated. Every entry's expression must be deterministic, since a STORED column's value
+// is persisted once on write rather than recomputed on read; NewVirtualColumnTableWithGenerated returns
+// ErrGeneratedColumnNonDeterministic if it isn't.
+func NewVirtualColumnTableWithGenerated(table sql.Table, projections []sql.Expression, generated map[string]sql.GeneratedColumnDetails) (*VirtualColumnTable, error) {
+	for name, gc := range generated {
+		if gc.Type != sql.GeneratedColumnType_Stored {
+			continue
+		}
+		if err := sql.CheckGeneratedColumnDeterministic(name, gc.Expression); err != nil {
+			return nil, err
+		}
+	}
+	return &VirtualColumnTable{
+		Table:       table,
+		Projections: projections,
+		Generated:   generated,
+	}, nil
+}
+
+// IndexedProjections returns this table's Projections, so an index catalog can match a storage engine's
+// sql.ExpressionIndex.IndexedExpressions against the same expressions a read of this table would otherwise
+// have to evaluate, and substitute an index lookup in their place.
+func (v *VirtualColumnTable) IndexedProjections() []sql.Expression {
+	return v.Projections
+}
+
 // WithExpressions implements the Expressioner interface.
 func (v *VirtualColumnTable) WithExpressions(exprs ...sql.Expression) (sql.TableWrapper, error) {
 	if len(exprs) != len(v.Projections) {
@@ -76,6 +114,111 @@ func (v *VirtualColumnTable) String() string {
 	return pr.String()
 }
 
+// Inserter implements the sql.InsertableTable interface, wrapping the underlying table's RowInserter so that
+// each row's STORED generated column values are computed here rather than trusted from the caller.
+func (v *VirtualColumnTable) Inserter(ctx *sql.Context) sql.RowInserter {
+	ins, ok := v.Table.(sql.InsertableTable)
+	if !ok {
+		return nil
+	}
+	return &generatedColumnInserter{RowInserter: ins.Inserter(ctx), table: v}
+}
+
+// Updater implements the sql.UpdatableTable interface, wrapping the underlying table's RowUpdater so that
+// each new row's STORED generated column values are computed here rather than trusted from the caller.
+func (v *VirtualColumnTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	upd, ok := v.Table.(sql.UpdatableTable)
+	if !ok {
+		return nil
+	}
+	return &generatedColumnUpdater{RowUpdater: upd.Updater(ctx), table: v}
+}
+
+// Replacer implements the sql.ReplaceableTable interface, wrapping the underlying table's RowReplacer so that
+// each row's STORED generated column values are computed here rather than trusted from the caller.
+func (v *VirtualColumnTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	rep, ok := v.Table.(sql.ReplaceableTable)
+	if !ok {
+		return nil
+	}
+	return &generatedColumnReplacer{RowReplacer: rep.Replacer(ctx), table: v}
+}
+
+// applyGeneratedColumns rejects an explicitly supplied value for any generated column - Virtual or Stored,
+// since MySQL computes both itself - and fills in this row's STORED columns by evaluating their expression
+// against it. A generated column's slot must be nil going in, the convention the analyzer rule that builds
+// an insert/update/replace row for a VirtualColumnTable is expected to follow for any column named in
+// Generated.
+func (v *VirtualColumnTable) applyGeneratedColumns(ctx *sql.Context, row sql.Row) (sql.Row, error) {
+	if len(v.Generated) == 0 {
+		return row, nil
+	}
+	schema := v.Table.Schema()
+	for i, col := range schema {
+		gc, ok := v.Generated[col.Name]
+		if !ok {
+			continue
+		}
+		if i < len(row) && row[i] != nil {
+			return nil, sql.ErrGeneratedColumnValueSupplied.New(col.Name, v.Name())
+		}
+		if gc.Type != sql.GeneratedColumnType_Stored {
+			continue
+		}
+		val, err := gc.Expression.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = val
+	}
+	return row, nil
+}
+
+// generatedColumnInserter wraps a sql.RowInserter, computing STORED generated column values before every
+// Insert reaches the underlying table.
+type generatedColumnInserter struct {
+	sql.RowInserter
+	table *VirtualColumnTable
+}
+
+func (i *generatedColumnInserter) Insert(ctx *sql.Context, row sql.Row) error {
+	row, err := i.table.applyGeneratedColumns(ctx, row)
+	if err != nil {
+		return err
+	}
+	return i.RowInserter.Insert(ctx, row)
+}
+
+// generatedColumnUpdater wraps a sql.RowUpdater, computing STORED generated column values for the new row
+// before every Update reaches the underlying table.
+type generatedColumnUpdater struct {
+	sql.RowUpdater
+	table *VirtualColumnTable
+}
+
+func (u *generatedColumnUpdater) Update(ctx *sql.Context, old, new sql.Row) error {
+	new, err := u.table.applyGeneratedColumns(ctx, new)
+	if err != nil {
+		return err
+	}
+	return u.RowUpdater.Update(ctx, old, new)
+}
+
+// generatedColumnReplacer wraps a sql.RowReplacer, computing STORED generated column values before every
+// Insert reaches the underlying table.
+type generatedColumnReplacer struct {
+	sql.RowReplacer
+	table *VirtualColumnTable
+}
+
+func (r *generatedColumnReplacer) Insert(ctx *sql.Context, row sql.Row) error {
+	row, err := r.table.applyGeneratedColumns(ctx, row)
+	if err != nil {
+		return err
+	}
+	return r.RowReplacer.Insert(ctx, row)
+}
+
 func (v *VirtualColumnTable) DebugString() string {
 	pr := sql.NewTreePrinter()
 	_ = pr.WriteNode("VirtualColumnTable")
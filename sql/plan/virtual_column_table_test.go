@@ -0,0 +1,118 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// storedColumnExpr is a minimal sql.Expression test double that evaluates to row[col] + 1 - enough to prove
+// VirtualColumnTable's Inserter actually evaluates and persists a STORED generated column's expression,
+// rather than just carrying it around unused.
+type storedColumnExpr struct {
+	col int
+}
+
+var _ sql.Expression = (*storedColumnExpr)(nil)
+
+func (e *storedColumnExpr) Resolved() bool             { return true }
+func (e *storedColumnExpr) String() string             { return "stored_column_expr" }
+func (e *storedColumnExpr) Type() sql.Type             { return types.Int64 }
+func (e *storedColumnExpr) IsNullable() bool           { return false }
+func (e *storedColumnExpr) Children() []sql.Expression { return nil }
+func (e *storedColumnExpr) Eval(_ *sql.Context, row sql.Row) (interface{}, error) {
+	return row[e.col].(int64) + 1, nil
+}
+func (e *storedColumnExpr) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(e, len(children), 0)
+	}
+	return e, nil
+}
+
+// insertRecordingTable is a minimal sql.InsertableTable test double that records every row handed to
+// Insert, so a test can assert on what VirtualColumnTable's generatedColumnInserter actually persisted.
+type insertRecordingTable struct {
+	name   string
+	schema sql.Schema
+	rows   []sql.Row
+}
+
+var _ sql.InsertableTable = (*insertRecordingTable)(nil)
+var _ sql.RowInserter = (*insertRecordingTable)(nil)
+
+func (t *insertRecordingTable) Name() string                                       { return t.name }
+func (t *insertRecordingTable) String() string                                     { return t.name }
+func (t *insertRecordingTable) Schema() sql.Schema                                 { return t.schema }
+func (t *insertRecordingTable) Collation() sql.CollationID                         { return sql.Collation_Default }
+func (t *insertRecordingTable) Partitions(*sql.Context) (sql.PartitionIter, error) { return nil, nil }
+func (t *insertRecordingTable) PartitionRows(*sql.Context, sql.Partition) (sql.RowIter, error) {
+	return sql.RowsToRowIter(t.rows...), nil
+}
+func (t *insertRecordingTable) Inserter(*sql.Context) sql.RowInserter    { return t }
+func (t *insertRecordingTable) StatementBegin(*sql.Context)              {}
+func (t *insertRecordingTable) DiscardChanges(*sql.Context, error) error { return nil }
+func (t *insertRecordingTable) StatementComplete(*sql.Context) error     { return nil }
+func (t *insertRecordingTable) Close(*sql.Context) error                 { return nil }
+func (t *insertRecordingTable) Insert(_ *sql.Context, row sql.Row) error {
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+// TestVirtualColumnTableInsertPersistsStoredColumnAndRejectsSuppliedValue wires NewVirtualColumnTableWithGenerated
+// into a real sql.InsertableTable and drives it through its Inserter, the way a CREATE TABLE ... AS (...) STORED
+// column's insert path is meant to: an inserted row that leaves the generated column nil gets its value computed
+// and persisted, while a row that supplies an explicit value for it is rejected outright.
+func TestVirtualColumnTableInsertPersistsStoredColumnAndRejectsSuppliedValue(t *testing.T) {
+	schema := sql.Schema{
+		{Name: "a", Type: types.Int64},
+		{Name: "b", Type: types.Int64},
+	}
+	generated := map[string]sql.GeneratedColumnDetails{
+		"b": {Type: sql.GeneratedColumnType_Stored, Expression: &storedColumnExpr{col: 0}},
+	}
+	underlying := &insertRecordingTable{name: "t", schema: schema}
+	projections := []sql.Expression{
+		expression.NewGetField(0, types.Int64, "a", false),
+		expression.NewGetField(1, types.Int64, "b", false),
+	}
+
+	vct, err := NewVirtualColumnTableWithGenerated(underlying, projections, generated)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := sql.NewEmptyContext()
+	ins := vct.Inserter(ctx)
+
+	if err := ins.Insert(ctx, sql.Row{int64(5), nil}); err != nil {
+		t.Fatalf("unexpected error inserting row with generated column left nil: %v", err)
+	}
+	if got := underlying.rows[0][1]; got != int64(6) {
+		t.Fatalf("expected stored column to be computed as 6, got %v", got)
+	}
+
+	err = ins.Insert(ctx, sql.Row{int64(5), int64(99)})
+	if !sql.ErrGeneratedColumnValueSupplied.Is(err) {
+		t.Fatalf("expected ErrGeneratedColumnValueSupplied, got %v", err)
+	}
+	if len(underlying.rows) != 1 {
+		t.Fatalf("rejected insert should not have reached the underlying table, got %d rows", len(underlying.rows))
+	}
+}
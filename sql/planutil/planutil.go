@@ -0,0 +1,225 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package planutil provides read-only introspection helpers over an analyzed sql.Node plan tree: walking it,
+// and collecting the joins, index accesses, and table order it contains. It exists so that external tools -
+// EXPLAIN pretty-printers, query-shape loggers, benchmark harnesses, plan-regression gates in downstream
+// projects - have a supported surface for the kind of plan-walking that test helpers have always needed to
+// do ad hoc, rather than each one copying its own private traversal.
+package planutil
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// Path is the chain of ancestors, root first, above the node currently passed to a WalkPlan visit func.
+type Path []sql.Node
+
+// Parent returns the node's immediate parent, or nil if the node is the root.
+func (p Path) Parent() sql.Node {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[len(p)-1]
+}
+
+// config holds the descent options shared by WalkPlan and the Collect* helpers.
+type config struct {
+	subqueries bool
+	ctes       bool
+}
+
+// Option configures how a walk descends into subqueries and CTEs.
+type Option func(*config)
+
+// defaultConfig descends into both subqueries and CTEs, matching the behavior the original private test
+// helpers this package replaces always had.
+func defaultConfig() config {
+	return config{subqueries: true, ctes: true}
+}
+
+// WithSubqueries controls whether a walk follows a *plan.Subquery expression into its inner plan.Query. On
+// by default.
+func WithSubqueries(descend bool) Option {
+	return func(c *config) { c.subqueries = descend }
+}
+
+// WithCTEs controls whether a walk descends into a *plan.SubqueryAlias's child (a CTE or derived table
+// definition) rather than treating it as an opaque named source. On by default.
+func WithCTEs(descend bool) Option {
+	return func(c *config) { c.ctes = descend }
+}
+
+// WalkPlan calls visit for root and every descendant reachable by following sql.Node children, subquery
+// expressions, and CTE/derived-table definitions (per opts). visit is called with the node and the path of
+// ancestors above it; returning false from visit skips that node's children (but sibling nodes are still
+// visited).
+func WalkPlan(root sql.Node, visit func(n sql.Node, path Path) bool, opts ...Option) {
+	cfg := defaultConfig()
+	for _, o := range opts {
+		o(&cfg)
+	}
+	walk(root, nil, cfg, visit)
+}
+
+func walk(n sql.Node, path Path, cfg config, visit func(sql.Node, Path) bool) {
+	if n == nil {
+		return
+	}
+	if !visit(n, path) {
+		return
+	}
+	childPath := append(append(Path{}, path...), n)
+
+	if _, ok := n.(*plan.SubqueryAlias); ok && !cfg.ctes {
+		return
+	}
+
+	for _, c := range n.Children() {
+		walk(c, childPath, cfg, visit)
+	}
+
+	if cfg.subqueries {
+		if ex, ok := n.(sql.Expressioner); ok {
+			for _, e := range ex.Expressions() {
+				transform.InspectExpr(e, func(e sql.Expression) bool {
+					sq, ok := e.(*plan.Subquery)
+					if !ok {
+						return true
+					}
+					walk(sq.Query, childPath, cfg, visit)
+					return false
+				})
+			}
+		}
+	}
+}
+
+// JoinInfo describes a single join node found by CollectJoins.
+type JoinInfo struct {
+	Op     plan.JoinType
+	Left   string
+	Right  string
+	Filter sql.Expression
+	// Lookup is true if Right is, or has anywhere beneath it, an IndexedTableAccess. A lookup join always
+	// reports true, but so does any join whose right side holds an indexed access further down (e.g. a hash
+	// join over a nested lookup join); Op is what names this join's own type.
+	Lookup bool
+}
+
+// CollectJoins returns info on every join node in root, in the order WalkPlan encounters them.
+func CollectJoins(root sql.Node, opts ...Option) []JoinInfo {
+	var joins []JoinInfo
+	WalkPlan(root, func(n sql.Node, _ Path) bool {
+		j, ok := n.(*plan.JoinNode)
+		if !ok {
+			return true
+		}
+		joins = append(joins, JoinInfo{
+			Op:     j.Op,
+			Left:   firstTableName(j.Left()),
+			Right:  firstTableName(j.Right()),
+			Filter: j.Filter,
+			Lookup: containsIndexedAccess(j.Right()),
+		})
+		return true
+	}, opts...)
+	return joins
+}
+
+// IndexAccessInfo describes a single indexed table access found by CollectIndexAccesses.
+type IndexAccessInfo struct {
+	Table string
+	Index sql.Index
+}
+
+// CollectIndexAccesses returns info on every IndexedTableAccess in root, in the order WalkPlan encounters
+// them.
+func CollectIndexAccesses(root sql.Node, opts ...Option) []IndexAccessInfo {
+	var accesses []IndexAccessInfo
+	WalkPlan(root, func(n sql.Node, _ Path) bool {
+		ita, ok := n.(*plan.IndexedTableAccess)
+		if !ok {
+			return true
+		}
+		accesses = append(accesses, IndexAccessInfo{
+			Table: firstTableName(ita),
+			Index: ita.Index(),
+		})
+		return true
+	}, opts...)
+	return accesses
+}
+
+// CollectTableOrder returns the names of every aliased table source (each TableAlias) in root, left to right
+// as written, the same order a STRAIGHT_JOIN or JOIN_ORDER hint would pin. An unaliased ResolvedTable or
+// IndexedTableAccess contributes no name.
+func CollectTableOrder(root sql.Node) []string {
+	var order []string
+	switch n := root.(type) {
+	case *plan.JoinNode:
+		order = append(order, CollectTableOrder(n.Left())...)
+		order = append(order, CollectTableOrder(n.Right())...)
+	case *plan.TableAlias:
+		order = append(order, n.Name())
+	default:
+		for _, c := range n.Children() {
+			order = append(order, CollectTableOrder(c)...)
+		}
+	}
+	return order
+}
+
+// firstTableName returns the name of the first named table source found under n, descending through joins
+// and other wrapper nodes, or "" if none is found.
+func firstTableName(n sql.Node) string {
+	var name string
+	WalkPlan(n, func(n sql.Node, _ Path) bool {
+		if name != "" {
+			return false
+		}
+		switch n := n.(type) {
+		case *plan.TableAlias:
+			name = n.Name()
+			return false
+		case *plan.ResolvedTable:
+			name = n.Name()
+			return false
+		case *plan.IndexedTableAccess:
+			name = n.Name()
+			return false
+		}
+		return true
+	})
+	return name
+}
+
+// containsIndexedAccess reports whether n is, or descends to, an IndexedTableAccess - the marker that a join
+// was planned to probe this side through an index rather than scan it in full.
+func containsIndexedAccess(n sql.Node) bool {
+	found := false
+	WalkPlan(n, func(n sql.Node, _ Path) bool {
+		if found {
+			return false
+		}
+		if _, ok := n.(*plan.IndexedTableAccess); ok {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
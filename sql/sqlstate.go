@@ -0,0 +1,82 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+// ErrorClass groups related SQLSTATE codes the way the MySQL manual does,
+// e.g. "Connection Exception", "Integrity Constraint Violation". It lets
+// callers branch on broad categories of failure (should this be retried?
+// surfaced to the user? treated as a bug?) without enumerating every
+// SQLSTATE individually.
+type ErrorClass string
+
+const (
+	ErrorClassSuccess                 ErrorClass = "00"
+	ErrorClassWarning                 ErrorClass = "01"
+	ErrorClassNoData                  ErrorClass = "02"
+	ErrorClassConnectionException     ErrorClass = "08"
+	ErrorClassFeatureNotSupported     ErrorClass = "0A"
+	ErrorClassIntegrityConstraint     ErrorClass = "23"
+	ErrorClassInvalidTransactionState ErrorClass = "25"
+	ErrorClassSyntaxErrorOrAccessRule ErrorClass = "42"
+	ErrorClassTransactionRollback     ErrorClass = "40"
+	ErrorClassDataException           ErrorClass = "22"
+	ErrorClassGeneralError            ErrorClass = "HY"
+)
+
+// SQLStateProvider is implemented by errors that know their own SQLSTATE,
+// rather than falling back to the default "HY000" / ER_UNKNOWN_ERROR
+// classification. Integrator-defined error kinds (e.g. from a custom
+// storage engine) can implement this to get precise client-facing
+// SQLSTATE codes without changing go-mysql-server's own error hierarchy.
+type SQLStateProvider interface {
+	error
+	// SQLState returns the 5-character SQLSTATE code for this error.
+	SQLState() string
+}
+
+// sqlStateClass returns the ErrorClass for a 5-character SQLSTATE code, or
+// ErrorClassGeneralError if the code's class prefix isn't recognized.
+func sqlStateClass(state string) ErrorClass {
+	if len(state) < 2 {
+		return ErrorClassGeneralError
+	}
+	switch ErrorClass(state[:2]) {
+	case ErrorClassSuccess, ErrorClassWarning, ErrorClassNoData,
+		ErrorClassConnectionException, ErrorClassFeatureNotSupported,
+		ErrorClassIntegrityConstraint, ErrorClassInvalidTransactionState,
+		ErrorClassSyntaxErrorOrAccessRule, ErrorClassTransactionRollback,
+		ErrorClassDataException:
+		return ErrorClass(state[:2])
+	default:
+		return ErrorClassGeneralError
+	}
+}
+
+// SQLStateForError returns the SQLSTATE code that should be reported to a
+// client for err. Errors implementing SQLStateProvider are honored
+// directly; otherwise the error falls back to "HY000", the generic MySQL
+// "unknown error" SQLSTATE.
+func SQLStateForError(err error) string {
+	if sp, ok := err.(SQLStateProvider); ok {
+		return sp.SQLState()
+	}
+	return "HY000"
+}
+
+// ErrorClassForError classifies err into a broad ErrorClass, using its
+// SQLSTATE if it provides one.
+func ErrorClassForError(err error) ErrorClass {
+	return sqlStateClass(SQLStateForError(err))
+}
@@ -63,7 +63,8 @@ func (d *expDistIter) Next(*sql.Context) (sql.Row, error) {
 	var ret sql.Row
 	ret = append(ret, d.i)
 	for i := 0; i < d.cols; i++ {
-		val := -math.Log2(rand.NormFloat64()) / d.lambda
+		// Inverse-CDF sampling for Exp(lambda) is -ln(U)/lambda, U ~ Uniform(0,1).
+		val := -math.Log(rand.Float64()) / d.lambda
 		if math.IsNaN(val) || math.IsInf(val, 0) {
 			val = math.MaxInt32
 		}
@@ -75,3 +76,174 @@ func (d *expDistIter) Next(*sql.Context) (sql.Row, error) {
 func (d *expDistIter) Close(*sql.Context) error {
 	return nil
 }
+
+// NewZipfDistIter returns rowCnt rows of colCnt Zipf-distributed integer columns in [0, imax], generated with
+// rand.NewZipf(rng, s, v, imax). Skewed towards low values the way a hot-key workload's access pattern is, for
+// stressing index selectivity and join cardinality estimates. rng may be nil, in which case a new unseeded
+// *rand.Rand is created; pass a seeded *rand.Rand for reproducible output in tests.
+func NewZipfDistIter(colCnt, rowCnt int, s, v float64, imax uint64, rng *rand.Rand) sql.RowIter {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return &zipfDistIter{cols: colCnt, cnt: rowCnt, zipf: rand.NewZipf(rng, s, v, imax)}
+}
+
+type zipfDistIter struct {
+	i    int
+	cols int
+	cnt  int
+	zipf *rand.Zipf
+}
+
+var _ sql.RowIter = (*zipfDistIter)(nil)
+
+func (d *zipfDistIter) Next(*sql.Context) (sql.Row, error) {
+	if d.i >= d.cnt {
+		return nil, io.EOF
+	}
+	d.i++
+	var ret sql.Row
+	ret = append(ret, d.i)
+	for i := 0; i < d.cols; i++ {
+		ret = append(ret, d.zipf.Uint64())
+	}
+	return ret, nil
+}
+
+func (d *zipfDistIter) Close(*sql.Context) error {
+	return nil
+}
+
+// NewUniformIntDistIter returns rowCnt rows of colCnt integer columns drawn uniformly from [lo, hi]. rng may be
+// nil, in which case a new unseeded *rand.Rand is created; pass a seeded *rand.Rand for reproducible output in
+// tests.
+func NewUniformIntDistIter(colCnt, rowCnt int, lo, hi int64, rng *rand.Rand) sql.RowIter {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return &uniformIntDistIter{cols: colCnt, cnt: rowCnt, lo: lo, hi: hi, rng: rng}
+}
+
+type uniformIntDistIter struct {
+	i      int
+	cols   int
+	cnt    int
+	lo, hi int64
+	rng    *rand.Rand
+}
+
+var _ sql.RowIter = (*uniformIntDistIter)(nil)
+
+func (d *uniformIntDistIter) Next(*sql.Context) (sql.Row, error) {
+	if d.i >= d.cnt {
+		return nil, io.EOF
+	}
+	d.i++
+	var ret sql.Row
+	ret = append(ret, d.i)
+	for i := 0; i < d.cols; i++ {
+		ret = append(ret, d.lo+d.rng.Int63n(d.hi-d.lo+1))
+	}
+	return ret, nil
+}
+
+func (d *uniformIntDistIter) Close(*sql.Context) error {
+	return nil
+}
+
+// NewPoissonDistIter returns rowCnt rows of colCnt columns drawn from a Poisson(lambda) distribution, generated via
+// Knuth's algorithm. rng may be nil, in which case a new unseeded *rand.Rand is created; pass a seeded *rand.Rand
+// for reproducible output in tests.
+func NewPoissonDistIter(colCnt, rowCnt int, lambda float64, rng *rand.Rand) sql.RowIter {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return &poissonDistIter{cols: colCnt, cnt: rowCnt, lambda: lambda, rng: rng}
+}
+
+type poissonDistIter struct {
+	i      int
+	cols   int
+	cnt    int
+	lambda float64
+	rng    *rand.Rand
+}
+
+var _ sql.RowIter = (*poissonDistIter)(nil)
+
+func (d *poissonDistIter) Next(*sql.Context) (sql.Row, error) {
+	if d.i >= d.cnt {
+		return nil, io.EOF
+	}
+	d.i++
+	var ret sql.Row
+	ret = append(ret, d.i)
+	for i := 0; i < d.cols; i++ {
+		ret = append(ret, poissonSample(d.rng, d.lambda))
+	}
+	return ret, nil
+}
+
+func (d *poissonDistIter) Close(*sql.Context) error {
+	return nil
+}
+
+// poissonSample draws a single sample from Poisson(lambda) using Knuth's algorithm: multiply uniform draws until
+// their running product drops below e^-lambda, counting how many draws that took.
+func poissonSample(rng *rand.Rand, lambda float64) int64 {
+	l := math.Exp(-lambda)
+	k := int64(0)
+	p := 1.0
+	for {
+		k++
+		p *= rng.Float64()
+		if p <= l {
+			return k - 1
+		}
+	}
+}
+
+// NewParetoDistIter returns rowCnt rows of colCnt columns drawn from a Pareto(xm, alpha) distribution via inverse-
+// CDF sampling: xm / U^(1/alpha), U ~ Uniform(0,1). rng may be nil, in which case a new unseeded *rand.Rand is
+// created; pass a seeded *rand.Rand for reproducible output in tests.
+func NewParetoDistIter(colCnt, rowCnt int, xm, alpha float64, rng *rand.Rand) sql.RowIter {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return &paretoDistIter{cols: colCnt, cnt: rowCnt, xm: xm, alpha: alpha, rng: rng}
+}
+
+type paretoDistIter struct {
+	i         int
+	cols      int
+	cnt       int
+	xm, alpha float64
+	rng       *rand.Rand
+}
+
+var _ sql.RowIter = (*paretoDistIter)(nil)
+
+func (d *paretoDistIter) Next(*sql.Context) (sql.Row, error) {
+	if d.i >= d.cnt {
+		return nil, io.EOF
+	}
+	d.i++
+	var ret sql.Row
+	ret = append(ret, d.i)
+	for i := 0; i < d.cols; i++ {
+		u := d.rng.Float64()
+		for u == 0 {
+			u = d.rng.Float64()
+		}
+		val := d.xm / math.Pow(u, 1/d.alpha)
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			val = math.MaxInt32
+		}
+		ret = append(ret, val)
+	}
+	return ret, nil
+}
+
+func (d *paretoDistIter) Close(*sql.Context) error {
+	return nil
+}
@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// genKind identifies which synthetic distribution a genTableFunction generates.
+type genKind byte
+
+const (
+	genKindZipf genKind = iota
+	genKindUniform
+	genKindPoisson
+	genKindPareto
+)
+
+// genTableFunctionArgCounts gives the number of distribution-specific parameters (after the leading row count)
+// each genKind's table function expects, matching its NewXDistIter counterpart in distributions.go.
+var genTableFunctionArgCounts = map[genKind]int{
+	genKindZipf:    3, // s, v, imax
+	genKindUniform: 2, // lo, hi
+	genKindPoisson: 1, // lambda
+	genKindPareto:  2, // xm, alpha
+}
+
+// genTableFunctionNames maps a table function's SQL name to the distribution it generates, e.g.
+// `SELECT * FROM zipf_gen(1000000, 1.1, 1.0, 1000)`.
+var genTableFunctionNames = map[string]genKind{
+	"zipf_gen":    genKindZipf,
+	"uniform_gen": genKindUniform,
+	"poisson_gen": genKindPoisson,
+	"pareto_gen":  genKindPareto,
+}
+
+// genTableFunction is a sql.TableFunction exposing NewZipfDistIter, NewUniformIntDistIter, NewPoissonDistIter, and
+// NewParetoDistIter as ad-hoc benchmark row sources. Every variant takes a row count as its first argument,
+// followed by that distribution's own parameters in the same order as its NewXDistIter constructor (minus the
+// column count and *rand.Rand, which this wrapper fixes at one value column and an unseeded generator
+// respectively).
+type genTableFunction struct {
+	name string
+	kind genKind
+	args []sql.Expression
+	db   sql.Database
+}
+
+var _ sql.Node = (*genTableFunction)(nil)
+var _ sql.Expressioner = (*genTableFunction)(nil)
+var _ sql.Databaser = (*genTableFunction)(nil)
+var _ sql.Nameable = (*genTableFunction)(nil)
+var _ sql.TableFunction = (*genTableFunction)(nil)
+
+// NewGenTableFunction returns the sql.TableFunction registered under name, or false if name doesn't identify one
+// of the distributions in genTableFunctionNames.
+func NewGenTableFunction(name string) (sql.TableFunction, bool) {
+	kind, ok := genTableFunctionNames[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return &genTableFunction{name: strings.ToLower(name), kind: kind}, true
+}
+
+// NewInstance implements the sql.TableFunction interface.
+func (g *genTableFunction) NewInstance(ctx *sql.Context, db sql.Database, args []sql.Expression) (sql.Node, error) {
+	wantArgs := 1 + genTableFunctionArgCounts[g.kind]
+	if len(args) != wantArgs {
+		return nil, fmt.Errorf("%s: expected %d arguments, got %d", g.name, wantArgs, len(args))
+	}
+	return &genTableFunction{name: g.name, kind: g.kind, args: args, db: db}, nil
+}
+
+// Name implements the sql.Nameable interface.
+func (g *genTableFunction) Name() string { return g.name }
+
+// Database implements the sql.Databaser interface.
+func (g *genTableFunction) Database() sql.Database { return g.db }
+
+// WithDatabase implements the sql.Databaser interface.
+func (g *genTableFunction) WithDatabase(db sql.Database) (sql.Node, error) {
+	ng := *g
+	ng.db = db
+	return &ng, nil
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (g *genTableFunction) Expressions() []sql.Expression { return g.args }
+
+// WithExpressions implements the sql.Expressioner interface.
+func (g *genTableFunction) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != len(g.args) {
+		return nil, sql.ErrInvalidChildrenNumber.New(g, len(exprs), len(g.args))
+	}
+	ng := *g
+	ng.args = exprs
+	return &ng, nil
+}
+
+// Resolved implements the sql.Node interface.
+func (g *genTableFunction) Resolved() bool {
+	for _, a := range g.args {
+		if !a.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// Children implements the sql.Node interface. genTableFunction is a leaf node; its arguments are Expressions, not
+// child Nodes.
+func (g *genTableFunction) Children() []sql.Node { return nil }
+
+// WithChildren implements the sql.Node interface.
+func (g *genTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(g, len(children), 0)
+	}
+	return g, nil
+}
+
+// Schema implements the sql.Node interface: a row number column alongside one value column, typed for the
+// distribution this instance generates.
+func (g *genTableFunction) Schema() sql.Schema {
+	valType := sql.Int64
+	if g.kind == genKindPareto {
+		valType = sql.Float64
+	}
+	return sql.Schema{
+		&sql.Column{Name: "row_num", Type: sql.Int64, Source: g.name},
+		&sql.Column{Name: "val", Type: valType, Source: g.name},
+	}
+}
+
+func (g *genTableFunction) String() string {
+	parts := make([]string, len(g.args))
+	for i, a := range g.args {
+		parts[i] = a.String()
+	}
+	return fmt.Sprintf("%s(%s)", g.name, strings.Join(parts, ", "))
+}
+
+// RowIter implements the sql.Node interface, evaluating this call's literal arguments and dispatching to this
+// genKind's NewXDistIter constructor.
+func (g *genTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	vals := make([]float64, len(g.args))
+	for i, a := range g.args {
+		v, err := a.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		f, err := sql.Float64.Convert(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: argument %d: %w", g.name, i+1, err)
+		}
+		vals[i] = f.(float64)
+	}
+	rowCnt := int(vals[0])
+
+	switch g.kind {
+	case genKindZipf:
+		return NewZipfDistIter(1, rowCnt, vals[1], vals[2], uint64(vals[3]), nil), nil
+	case genKindUniform:
+		return NewUniformIntDistIter(1, rowCnt, int64(vals[1]), int64(vals[2]), nil), nil
+	case genKindPoisson:
+		return NewPoissonDistIter(1, rowCnt, vals[1], nil), nil
+	case genKindPareto:
+		return NewParetoDistIter(1, rowCnt, vals[1], vals[2], nil), nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported generator kind", g.name)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+// StraightJoinNode is implemented by a plan.Node parsed from `SELECT STRAIGHT_JOIN ...` - MySQL's keyword
+// form of forcing join order, equivalent to an `/*+ JOIN_FIXED_ORDER */` or `/*+ STRAIGHT_JOIN */` hint. The
+// join-order reorder rule checks IsStraightJoin before invoking its DP-based cost search and, when true,
+// preserves the node's tables in FROM-clause order instead (see memo.Memo.WithStraightJoin).
+type StraightJoinNode interface {
+	Node
+	// IsStraightJoin returns true if this node's join order must be preserved as written.
+	IsStraightJoin() bool
+}
@@ -16,6 +16,8 @@ package sql
 
 import (
 	"fmt"
+	"io"
+	"strings"
 )
 
 // Table is a SQL table.
@@ -167,6 +169,18 @@ type IndexBuildingTable interface {
 	BuildIndex(ctx *Context, indexDef IndexDef) (RowInserter, error)
 }
 
+// ExpressionIndex is implemented by an Index whose storage engine has persisted it over one or more arbitrary
+// expressions - e.g. json_extract(j, '$.a') - rather than over a literal set of columns, the way MySQL 8's
+// functional indexes work. A table exposing generated columns via plan.VirtualColumnTable uses
+// IndexedExpressions to match a predicate on the underlying column against the same expression the index was
+// built over, so the predicate can be rewritten into an index lookup instead of a full scan with a Filter on
+// top.
+type ExpressionIndex interface {
+	Index
+	// IndexedExpressions returns the expressions this index is built over, in key order.
+	IndexedExpressions() []Expression
+}
+
 // ForeignKeyTable is a table that declares foreign key constraints, and can be referenced by other tables' foreign
 // key constraints.
 type ForeignKeyTable interface {
@@ -197,6 +211,152 @@ type ForeignKeyEditor interface {
 	IndexAddressable
 }
 
+// RecordIterableTable is an optional interface, alongside ProjectedTable, for a table whose storage engine can
+// stream rows directly through a callback instead of the engine driving Partitions and PartitionRows itself. A
+// purely scan-driven plan - a full table scan feeding an aggregate, or a dump/export path - can use this to avoid
+// the per-partition iterator allocation Partitions/PartitionRows otherwise requires, and to resume a scan midway
+// via startKey rather than restarting it. IterRecordsFromPartitions adapts any Table to this interface for
+// integrators that don't implement it directly.
+type RecordIterableTable interface {
+	Table
+	// IterRecords calls fn once for each row in the table whose key is >= startKey (a nil startKey scans from the
+	// beginning), restricting decoded columns to cols when non-nil. fn returns more=false to stop iteration early
+	// without an error. IterRecords returns any error fn returns, or one of its own.
+	IterRecords(ctx *Context, startKey []byte, cols []string, fn func(handle int64, row Row) (more bool, err error)) error
+}
+
+// IterRecordsFromPartitions adapts any Table to RecordIterableTable's callback shape by driving its existing
+// Partitions/PartitionRows iterators itself. It takes no startKey or cols - a Table with no native notion of a
+// record key can't resume mid-scan - so this adapter always starts from the beginning and passes fn full rows; a
+// table wanting real startKey-based
+// resume should implement RecordIterableTable directly instead of relying on this adapter.
+func IterRecordsFromPartitions(ctx *Context, table Table, fn func(handle int64, row Row) (more bool, err error)) error {
+	partitions, err := table.Partitions(ctx)
+	if err != nil {
+		return err
+	}
+	defer partitions.Close(ctx)
+
+	var handle int64
+	for {
+		partition, err := partitions.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rowIter, err := table.PartitionRows(ctx, partition)
+		if err != nil {
+			return err
+		}
+		for {
+			row, err := rowIter.Next(ctx)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				rowIter.Close(ctx)
+				return err
+			}
+			more, err := fn(handle, row)
+			handle++
+			if err != nil {
+				rowIter.Close(ctx)
+				return err
+			}
+			if !more {
+				rowIter.Close(ctx)
+				return nil
+			}
+		}
+		if err := rowIter.Close(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// EmptyTableChecker is an optional interface a ForeignKeyTable can implement to report whether it currently holds
+// any rows. The analyzer's FK-add handling consults this when a FOREIGN KEY is being added whose referencing
+// columns aren't covered by any existing index: if the table reports empty, it synthesizes an IndexDef (named
+// deterministically, e.g. "<fkname>_idx"), calls CreateIndexForForeignKey, and proceeds with AddForeignKey, which
+// otherwise would have to reject the constraint outright. A table that doesn't implement EmptyTableChecker, or
+// reports itself non-empty, still gets the FK rejected for lacking a covering index - there's no way to check
+// every existing row against the new constraint without one.
+type EmptyTableChecker interface {
+	// IsEmpty reports whether the table currently holds any rows.
+	IsEmpty(ctx *Context) (bool, error)
+}
+
+// EnsureForeignKeyIndex finds an index on table covering columns, in order, suitable for backing a new foreign key
+// named fkName. If none exists, it creates one via CreateIndexForForeignKey - naming it deterministically, fkName
+// plus "_idx" - but only when table reports itself empty through EmptyTableChecker, since there'd otherwise be no
+// way to know the new constraint is satisfied by every existing row. This lets AddForeignKey succeed for the common
+// ORM pattern of a CREATE TABLE followed by a separate ALTER TABLE ... ADD CONSTRAINT ... FOREIGN KEY, issued before
+// any rows exist. It returns an error if no covering index exists and table is non-empty, or doesn't implement
+// EmptyTableChecker at all.
+func EnsureForeignKeyIndex(ctx *Context, table ForeignKeyTable, fkName string, columns []string) error {
+	indexes, err := table.GetIndexes(ctx)
+	if err != nil {
+		return err
+	}
+	if indexCoversColumns(indexes, columns) {
+		return nil
+	}
+
+	checker, ok := table.(EmptyTableChecker)
+	if !ok {
+		return fmt.Errorf("no index covers foreign key %q's columns, and %s does not support automatic index creation", fkName, table.Name())
+	}
+	empty, err := checker.IsEmpty(ctx)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return fmt.Errorf("no index covers foreign key %q's columns, and table %s is not empty", fkName, table.Name())
+	}
+
+	idxColumns := make([]IndexColumn, len(columns))
+	for i, col := range columns {
+		idxColumns[i] = IndexColumn{Name: col}
+	}
+	return table.CreateIndexForForeignKey(ctx, IndexDef{
+		Name:    fkName + "_idx",
+		Columns: idxColumns,
+	})
+}
+
+// indexCoversColumns reports whether any index in indexes has columns as its leading columns, in the same order.
+func indexCoversColumns(indexes []Index, columns []string) bool {
+	for _, idx := range indexes {
+		exprs := idx.Expressions()
+		if len(exprs) < len(columns) {
+			continue
+		}
+		covers := true
+		for i, col := range columns {
+			if !indexExpressionMatchesColumn(exprs[i], col) {
+				covers = false
+				break
+			}
+		}
+		if covers {
+			return true
+		}
+	}
+	return false
+}
+
+// indexExpressionMatchesColumn compares an index's expression string (typically "table.column") against a bare
+// column name.
+func indexExpressionMatchesColumn(expr, col string) bool {
+	if i := strings.LastIndexByte(expr, '.'); i >= 0 {
+		expr = expr[i+1:]
+	}
+	return strings.EqualFold(expr, col)
+}
+
 // ReferenceChecker is usually an IndexAddressableTable that does key
 // lookups for existence checks. Indicating that the engine is performing
 // a reference check lets the integrator avoid expensive deserialization
@@ -288,6 +448,19 @@ type DeletableTable interface {
 	Deleter(*Context) RowDeleter
 }
 
+// RangeDeletableTable is an optional extension of DeletableTable for a table that can delete a range of rows
+// directly from an index lookup or whole-partition scan, without the engine opening a RowDeleter and streaming each
+// row through Delete. The delete planner only dispatches to DeleteRange when the statement's predicate is fully
+// covered by lookup/filters, the table has no CheckTable constraints or HookableTable hooks that require seeing
+// the old row, and every inbound foreign key uses NO ACTION/RESTRICT and has no referencing rows - anything else
+// forces the row-by-row RowDeleter path instead.
+type RangeDeletableTable interface {
+	DeletableTable
+	// DeleteRange deletes every row matched by lookup and filters without materializing them, returning the number
+	// of rows deleted for the rows_affected result.
+	DeleteRange(ctx *Context, lookup IndexLookup, filters []Expression) (int64, error)
+}
+
 // RowDeleter is a delete cursor that can delete one or more rows from a table.
 type RowDeleter interface {
 	EditOpenerCloser
@@ -299,6 +472,19 @@ type RowDeleter interface {
 	Closer
 }
 
+// BatchRowDeleter is an optional extension of RowDeleter for a storage engine that can combine many per-row
+// deletes into a single write batch - common for KV- or LSM-backed tables, where a batched write amortizes
+// the cost that would otherwise be paid once per Delete call. plan.deleteIter buffers rows up to
+// DeleteBatchSizeSessionVar before calling DeleteBatch, falling back to Delete one row at a time for a
+// RowDeleter that doesn't implement this.
+type BatchRowDeleter interface {
+	RowDeleter
+	// DeleteBatch deletes every row in rows. It's called in place of one Delete call per row; unlike Delete,
+	// which returns ErrDeleteRowNotFound, it should skip a row found to not exist rather than treat it as an
+	// error.
+	DeleteBatch(ctx *Context, rows []Row) error
+}
+
 // TruncateableTable is a table that can process the deletion of all rows either via a TRUNCATE TABLE statement or a
 // DELETE statement without a WHERE clause. This is usually much faster that deleting rows one at a time.
 type TruncateableTable interface {
@@ -349,6 +535,34 @@ type RowReplacer interface {
 	RowDeleter
 }
 
+// HookableTable is a table that wants to observe or veto row changes around insert, update, and delete operations,
+// and around rows as they're read back out, without having to wrap a RowInserter/RowUpdater/RowDeleter/RowIter
+// itself. This mirrors the before/after hook model common in ORMs like gorp: a hook may mutate the row it's given by
+// returning a replacement, or abort the operation in progress by returning a non-nil error, which the engine
+// propagates through the editor's DiscardChanges path the same as any other error from Insert/Update/Delete.
+type HookableTable interface {
+	Table
+	// BeforeInsert is called with a row about to be inserted, before RowInserter.Insert runs. The returned row is
+	// the one actually inserted.
+	BeforeInsert(ctx *Context, row Row) (Row, error)
+	// AfterInsert is called with the row that was just inserted, after RowInserter.Insert returns successfully.
+	AfterInsert(ctx *Context, row Row) error
+	// BeforeUpdate is called with the old and new versions of a row about to be updated, before RowUpdater.Update
+	// runs. The returned row replaces new as the one actually written.
+	BeforeUpdate(ctx *Context, old Row, new Row) (Row, error)
+	// AfterUpdate is called with the old and new versions of a row that was just updated, after RowUpdater.Update
+	// returns successfully.
+	AfterUpdate(ctx *Context, old Row, new Row) error
+	// BeforeDelete is called with a row about to be deleted, before RowDeleter.Delete runs. The returned row is the
+	// one actually deleted.
+	BeforeDelete(ctx *Context, row Row) (Row, error)
+	// AfterDelete is called with the row that was just deleted, after RowDeleter.Delete returns successfully.
+	AfterDelete(ctx *Context, row Row) error
+	// PostGet is called by this table's own PartitionRows implementation with each row as it's read back out, before
+	// that row is returned to the caller. A non-nil error aborts iteration.
+	PostGet(ctx *Context, row Row) error
+}
+
 // UpdatableTable is a table that can process updates of existing rows via update statements.
 type UpdatableTable interface {
 	Table
@@ -479,3 +693,43 @@ type IndexSearchableTable interface {
 	IndexAddressableTable
 	IndexSearchable
 }
+
+// RowLockMode describes the row-level locking behavior requested for a
+// table scan performed on behalf of SELECT ... FOR UPDATE / LOCK IN SHARE
+// MODE.
+type RowLockMode uint8
+
+const (
+	// RowLockMode_None indicates no row locking was requested.
+	RowLockMode_None RowLockMode = iota
+	// RowLockMode_Exclusive corresponds to FOR UPDATE.
+	RowLockMode_Exclusive
+	// RowLockMode_Shared corresponds to LOCK IN SHARE MODE / FOR SHARE.
+	RowLockMode_Shared
+)
+
+// SpatialIndex is an index backed by an R-tree (or similar) structure over
+// a GEOMETRY column, letting the optimizer probe for rows whose geometry
+// intersects a bounding box rather than scanning every row.
+type SpatialIndex interface {
+	Index
+	// RowIterForBoundingBox returns an iterator over the rows whose indexed
+	// geometry's minimum bounding rectangle overlaps the box given by
+	// minX, minY, maxX, maxY. The caller is responsible for rechecking the
+	// exact geometry predicate against candidates, since the MBR probe is
+	// only a superset filter.
+	RowIterForBoundingBox(ctx *Context, minX, minY, maxX, maxY float64) (RowIter, error)
+}
+
+// LockingTable is a table that can take row-level locks on the rows it
+// returns from a partition scan, for use by SELECT ... FOR UPDATE and
+// LOCK IN SHARE MODE. Integrators that don't implement this interface will
+// have locking clauses accepted but silently ignored, matching MySQL's
+// behavior for storage engines without row-level locking.
+type LockingTable interface {
+	Table
+	// WithRowLock returns a version of this table that acquires the given
+	// row lock mode on every row read from PartitionRows while the
+	// enclosing transaction is open.
+	WithRowLock(mode RowLockMode) Table
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"errors"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types/jsonpath"
+)
+
+// JSONNode is an opaque handle to a value reached while walking a
+// LazyJSONValue, without that value necessarily having been unmarshalled
+// into Go-native types yet.
+type JSONNode interface {
+	// TypeOf mirrors JSONValue.TypeOf for this node alone.
+	TypeOf(ctx *sql.Context) (JsonTypeCategory, error)
+	// Value forces this node to resolve to a concrete JSONValue.
+	Value(ctx *sql.Context) (JSONValue, error)
+}
+
+// LazyJSONValue is implemented by a JSONValue backed by something other
+// than a fully-materialized Go tree - Dolt's chunked/indexed JSON document
+// storage is the canonical example. JSONDocument does not implement it: it
+// is always already fully in memory, so WalkPath/ScanArray would have
+// nothing to save it, and it keeps using the plain Go-type-switch fast
+// path in containsJSON/compareJSON/Extract.
+//
+// JSONDocument.Contains uses ScanArray instead of Unmarshall when the
+// document is an array and the candidate is a LazyJSONValue holding an
+// array, so that a lazily-backed candidate only pays to materialize the
+// elements the containment check has to look at before it can
+// short-circuit, instead of the whole document up front. Comparison does
+// not use this interface: Compare and compareJSON unmarshal the other
+// operand. See containsJSONArrayLazy and ExtractLazy for the two call
+// sites that take advantage of it today.
+type LazyJSONValue interface {
+	JSONValue
+
+	// WalkPath resolves path without a full Unmarshall, invoking visit
+	// once per segment with the node reached after that segment is
+	// applied. A non-nil error from visit aborts the walk early and is
+	// returned to the caller - including a sentinel "found what I wanted"
+	// error, which the caller should filter back out, mirroring how
+	// containsJSONArrayLazy below uses errStopScan.
+	WalkPath(ctx *sql.Context, path jsonpath.Path, visit func(segment jsonpath.PathSegment, node JSONNode) error) error
+
+	// ScanArray calls visit once per element of a top-level JSON array
+	// value, in index order, stopping early if visit returns a non-nil
+	// error (again, including a deliberate early-stop sentinel). It
+	// returns sql.ErrInvalidType if the value isn't an array.
+	ScanArray(ctx *sql.Context, visit func(i int, elem JSONValue) error) error
+}
+
+// errStopScan is returned by a ScanArray/WalkPath visitor to end the walk
+// early once it has the answer it needs; callers must not propagate it.
+var errStopScan = errors.New("stop scan")
+
+// containsJSONArrayLazy is containsJSONArray's "b is also an array" case -
+// every element of b must be found somewhere in a - but scans b through
+// LazyJSONValue.ScanArray instead of unmarshalling it up front, so a
+// lazily-backed candidate only materializes elements up to the first one
+// that isn't found in a.
+func containsJSONArrayLazy(ctx *sql.Context, a []interface{}, b LazyJSONValue) (bool, error) {
+	cat, err := b.TypeOf(ctx)
+	if err != nil {
+		return false, err
+	}
+	if cat != JsonTypeArray {
+		unmarshalled, err := b.Unmarshall(ctx)
+		if err != nil {
+			return false, err
+		}
+		return containsJSONArray(a, unmarshalled.Val)
+	}
+
+	allFound := true
+	err = b.ScanArray(ctx, func(i int, elem JSONValue) error {
+		resolved, err := elem.Unmarshall(ctx)
+		if err != nil {
+			return err
+		}
+		ok, err := containsJSONArray(a, resolved.Val)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		allFound = false
+		return errStopScan
+	})
+	if err != nil && err != errStopScan {
+		return false, err
+	}
+	return allFound, nil
+}
+
+// ExtractLazy is a reusable JSON_EXTRACT implementation for a
+// LazyJSONValue: it walks path via WalkPath instead of unmarshalling lv up
+// front, so a chunked/indexed JSON value can provide the same Extract
+// behavior as JSONDocument.Extract without materializing any part of the
+// document the path doesn't touch. A LazyJSONValue implementation's own
+// Extract method is expected to call this rather than re-deriving it.
+func ExtractLazy(ctx *sql.Context, lv LazyJSONValue, path string) (JSONValue, error) {
+	if path == "$" {
+		return lv, nil
+	}
+	p, err := jsonpath.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result JSONValue
+	found := false
+	err = lv.WalkPath(ctx, p, func(segment jsonpath.PathSegment, node JSONNode) error {
+		v, err := node.Value(ctx)
+		if err != nil {
+			return err
+		}
+		result = v
+		found = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return result, nil
+}
@@ -17,12 +17,12 @@ package types
 import (
 	"database/sql/driver"
 	"fmt"
+	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
-	"github.com/dolthub/jsonpath"
+	"github.com/dolthub/go-mysql-server/sql/types/jsonpath"
 )
 
 // JSONValue is an integrator specific implementation of a JSON field value.
@@ -34,6 +34,74 @@ type JSONValue interface {
 	Compare(ctx *sql.Context, v JSONValue) (cmp int, err error)
 	// ToString marshalls a JSONValue to a valid JSON-encoded string.
 	ToString(ctx *sql.Context) (string, error)
+	// TypeOf returns the JsonTypeCategory of the root value, i.e. the
+	// answer JSON_TYPE() would give. Implementations backed by something
+	// other than a fully-unmarshalled tree (see SearchableJSONValue) can
+	// often answer this by inspecting just the first byte of their
+	// serialized form, without paying the cost of a full Unmarshall.
+	TypeOf(ctx *sql.Context) (JsonTypeCategory, error)
+}
+
+// JsonTypeCategory classifies a JSON value by the coarse category MySQL
+// uses for both JSON_TYPE() and cross-value comparison precedence. The
+// constants are declared from highest precedence (BLOB) to lowest (NULL).
+// Values from a lower-precedence category always compare less than values
+// from a higher-precedence one; only values in the same category, or two
+// numbers (INTEGER, DOUBLE, NUMBER), fall through to a value-level
+// comparison.
+//
+// https://dev.mysql.com/doc/refman/8.0/en/json.html#json-comparison
+type JsonTypeCategory uint8
+
+const (
+	JsonTypeBlob JsonTypeCategory = iota
+	JsonTypeBit
+	JsonTypeOpaque
+	JsonTypeDatetime
+	JsonTypeDate
+	JsonTypeTime
+	JsonTypeBoolean
+	JsonTypeArray
+	JsonTypeObject
+	JsonTypeString
+	JsonTypeInteger
+	JsonTypeDouble
+	JsonTypeNumber // reserved for categories that don't distinguish INTEGER/DOUBLE
+	JsonTypeNull
+)
+
+// String returns the MySQL JSON_TYPE() label for the category.
+func (c JsonTypeCategory) String() string {
+	switch c {
+	case JsonTypeBlob:
+		return "BLOB"
+	case JsonTypeBit:
+		return "BIT"
+	case JsonTypeOpaque:
+		return "OPAQUE"
+	case JsonTypeDatetime:
+		return "DATETIME"
+	case JsonTypeDate:
+		return "DATE"
+	case JsonTypeTime:
+		return "TIME"
+	case JsonTypeBoolean:
+		return "BOOLEAN"
+	case JsonTypeArray:
+		return "ARRAY"
+	case JsonTypeObject:
+		return "OBJECT"
+	case JsonTypeString:
+		return "STRING"
+	case JsonTypeInteger:
+		return "INTEGER"
+	case JsonTypeDouble, JsonTypeNumber:
+		return "DOUBLE"
+	case JsonTypeNull:
+		return "NULL"
+	default:
+		return "OPAQUE"
+	}
 }
 
 // SearchableJSONValue is JSONValue supporting in-place access operations.
@@ -50,8 +118,13 @@ type SearchableJSONValue interface {
 	Keys(ctx *sql.Context, path string) (val JSONValue, err error)
 	// Overlaps is value-specific implementation of JSON_Overlaps()
 	Overlaps(ctx *sql.Context, val SearchableJSONValue) (ok bool, err error)
-	// Search is value-specific implementation of JSON_Search()
-	Search(ctx *sql.Context) (path string, err error)
+	// Search is value-specific implementation of JSON_Search(). oneOrAll is
+	// "one" or "all"; searchStr is a LIKE-style pattern (`%`/`_` wildcards,
+	// escapable via escape, which is empty for the default `\`); paths, if
+	// given, restrict the search to those JSON paths. The result is nil if
+	// nothing matched, a JSON string path for a lone match, or a JSON array
+	// of paths when "all" finds more than one.
+	Search(ctx *sql.Context, oneOrAll string, searchStr string, escape string, paths ...string) (result interface{}, err error)
 }
 
 type MutableJSONValue interface {
@@ -65,6 +138,21 @@ type MutableJSONValue interface {
 	// Replace the value
 	Replace(ctx *sql.Context, path string, val JSONValue) (MutableJSONValue, bool, error)
 
+	// MergePatch applies an RFC 7396 JSON Merge Patch, implementing
+	// JSON_MERGE_PATCH(). Unlike Insert/Set/Replace, the patch document's
+	// own structure determines which paths are touched: an object key
+	// mapped to JSON null removes that key from the target, an object key
+	// mapped to any other value recursively merges in, and a patch that is
+	// not itself an object replaces the target wholesale.
+	MergePatch(ctx *sql.Context, patch JSONValue) (MutableJSONValue, bool, error)
+
+	// ApplyPatch applies an RFC 6902 JSON Patch document, implementing
+	// JSON_PATCH(). Unlike MergePatch, the patch is an ordered array of
+	// explicit operations (add/remove/replace/move/copy/test) addressed by
+	// RFC 6901 JSON Pointer rather than a structural overlay. The bool
+	// result is whether every `test` operation in the patch passed.
+	ApplyPatch(ctx *sql.Context, patch JSONValue) (MutableJSONValue, bool, error)
+
 	// NM4 build and convert functions required.
 }
 
@@ -79,6 +167,29 @@ func (doc JSONDocument) Unmarshall(_ *sql.Context) (JSONDocument, error) {
 }
 
 func (doc JSONDocument) Compare(ctx *sql.Context, v JSONValue) (int, error) {
+	// If the two values fall in different comparison-precedence categories,
+	// the category ordering alone decides the result, and we can avoid
+	// unmarshalling |v| at all when it can answer TypeOf on its own (e.g. a
+	// SearchableJSONValue backed by a tree or index).
+	aCat, err := doc.TypeOf(ctx)
+	if err != nil {
+		return 0, err
+	}
+	bCat, err := v.TypeOf(ctx)
+	if err != nil {
+		return 0, err
+	}
+	isNumber := func(c JsonTypeCategory) bool {
+		return c == JsonTypeInteger || c == JsonTypeDouble || c == JsonTypeNumber
+	}
+	if aCat != bCat && !(isNumber(aCat) && isNumber(bCat)) {
+		// A lower constant is a higher precedence, so it compares greater.
+		if aCat < bCat {
+			return 1, nil
+		}
+		return -1, nil
+	}
+
 	other, err := v.Unmarshall(ctx)
 	if err != nil {
 		return 0, err
@@ -90,12 +201,49 @@ func (doc JSONDocument) ToString(_ *sql.Context) (string, error) {
 	return marshalToMySqlString(doc.Val)
 }
 
+// TypeOf implements JSONValue. JSONDocument is always fully unmarshalled,
+// so this is a plain Go type switch over doc.Val; implementations backed by
+// a lazier representation can do better (see the SearchableJSONValue doc
+// comment).
+func (doc JSONDocument) TypeOf(_ *sql.Context) (JsonTypeCategory, error) {
+	switch v := doc.Val.(type) {
+	case nil:
+		return JsonTypeNull, nil
+	case bool:
+		return JsonTypeBoolean, nil
+	case []interface{}:
+		return JsonTypeArray, nil
+	case map[string]interface{}:
+		return JsonTypeObject, nil
+	case string:
+		return JsonTypeString, nil
+	case float64:
+		if v == float64(int64(v)) {
+			return JsonTypeInteger, nil
+		}
+		return JsonTypeDouble, nil
+	default:
+		return JsonTypeOpaque, nil
+	}
+}
+
 var _ SearchableJSONValue = JSONDocument{}
 var _ MutableJSONValue = JSONDocument{}
 
 // Contains returns nil in case of a nil value for either the doc.Val or candidate. Otherwise
 // it returns a bool
 func (doc JSONDocument) Contains(ctx *sql.Context, candidate JSONValue) (val interface{}, err error) {
+	if a, ok := doc.Val.([]interface{}); ok {
+		if lazy, ok := candidate.(LazyJSONValue); ok {
+			cat, err := lazy.TypeOf(ctx)
+			if err != nil {
+				return false, err
+			}
+			if cat == JsonTypeArray {
+				return containsJSONArrayLazy(ctx, a, lazy)
+			}
+		}
+	}
 	other, err := candidate.Unmarshall(ctx)
 	if err != nil {
 		return false, err
@@ -109,41 +257,273 @@ func (doc JSONDocument) Extract(ctx *sql.Context, path string) (JSONValue, error
 		return doc, nil
 	}
 
-	c, err := jsonpath.Compile(path)
+	// A lookup against a json null always resolves to SQL NULL, except for
+	// the identity lookup $ handled above.
+	if doc.Val == nil {
+		return nil, nil
+	}
+
+	p, err := jsonpath.ParsePath(path)
 	if err != nil {
 		return nil, err
 	}
 
-	// Lookup(obj) throws an error if obj is nil. We want lookups on a json null
-	// to always result in sql NULL, except in the case of the identity lookup
-	// $.
-	r := doc.Val
-	if r == nil {
+	matches, err := p.Get(doc.Val)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		// A missing key, or a wildcard matching nothing, results in SQL NULL.
+		return nil, nil
+	}
+	if p.HasWildcard() {
+		return JSONDocument{Val: matches}, nil
+	}
+	return JSONDocument{Val: matches[0]}, nil
+}
+
+// Keys returns the top-level keys of the object found at path, as a JSON
+// array, or nil if path resolves to a non-object or doesn't resolve at all.
+func (doc JSONDocument) Keys(ctx *sql.Context, path string) (val JSONValue, err error) {
+	target, err := doc.Extract(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, nil
+	}
+	resolved, err := target.Unmarshall(ctx)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := resolved.Val.(map[string]interface{})
+	if !ok {
 		return nil, nil
 	}
 
-	val, err := c.Lookup(r)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	arr := make([]interface{}, len(keys))
+	for i, k := range keys {
+		arr[i] = k
+	}
+	return JSONDocument{Val: arr}, nil
+}
+
+// Overlaps implements JSON_OVERLAPS' MySQL 8.0 semantics: two arrays
+// overlap if any element compares equal; an array and a non-array value
+// (scalar or object) overlap if that value matches one of the array's
+// elements; two objects overlap if
+// any key is present in both with an equal value; otherwise (including two
+// scalars) they overlap only if directly equal.
+func (doc JSONDocument) Overlaps(ctx *sql.Context, val SearchableJSONValue) (ok bool, err error) {
+	other, err := val.Unmarshall(ctx)
 	if err != nil {
-		if strings.Contains(err.Error(), "key error") {
-			// A missing key results in a SQL null
-			return nil, nil
+		return false, err
+	}
+	return jsonOverlaps(doc.Val, other.Val)
+}
+
+func jsonOverlaps(a, b interface{}) (bool, error) {
+	aArr, aIsArr := a.([]interface{})
+	bArr, bIsArr := b.([]interface{})
+
+	switch {
+	case aIsArr && bIsArr:
+		for _, ae := range aArr {
+			for _, be := range bArr {
+				if jsonValuesEqual(ae, be) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	case aIsArr:
+		for _, ae := range aArr {
+			if jsonValuesEqual(ae, b) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case bIsArr:
+		return jsonOverlaps(b, a)
+	default:
+		aObj, aIsObj := a.(map[string]interface{})
+		bObj, bIsObj := b.(map[string]interface{})
+		if aIsObj && bIsObj {
+			for k, av := range aObj {
+				if bv, ok := bObj[k]; ok && jsonValuesEqual(av, bv) {
+					return true, nil
+				}
+			}
+			return false, nil
 		}
+		return jsonValuesEqual(a, b), nil
+	}
+}
+
+// jsonValuesEqual reports whether a and b compare equal under compareJSON,
+// treating incomparable types (e.g. differing categories) as unequal rather
+// than an error: JSON_OVERLAPS never errors on a type mismatch, it's simply
+// "no overlap".
+func jsonValuesEqual(a, b interface{}) bool {
+	cmp, err := compareJSON(a, b)
+	return err == nil && cmp == 0
+}
+
+// Search implements JSON_SEARCH: a LIKE-style scan (with % and _
+// wildcards) of every string scalar reachable from doc.Val, restricted to
+// |paths| when given, returning the path(s) of matches. oneOrAll is "one"
+// (stop at the first match, in a depth-first, key-sorted traversal order)
+// or "all" (collect every match into a JSON array; a single match is still
+// returned as a bare string, matching MySQL).
+func (doc JSONDocument) Search(ctx *sql.Context, oneOrAll string, searchStr string, escape string, paths ...string) (interface{}, error) {
+	pattern, err := compileJSONSearchPattern(searchStr, escape)
+	if err != nil {
 		return nil, err
 	}
+	all := strings.EqualFold(oneOrAll, "all")
+	if !all && !strings.EqualFold(oneOrAll, "one") {
+		return nil, fmt.Errorf("The oneOrAll argument to JSON_SEARCH may take these values: 'one' or 'all'")
+	}
+
+	var roots []interface{}
+	var rootPaths []string
+	if len(paths) == 0 {
+		roots = []interface{}{doc.Val}
+		rootPaths = []string{"$"}
+	} else {
+		for _, p := range paths {
+			target, err := doc.Extract(ctx, p)
+			if err != nil {
+				return nil, err
+			}
+			if target == nil {
+				continue
+			}
+			resolved, err := target.Unmarshall(ctx)
+			if err != nil {
+				return nil, err
+			}
+			roots = append(roots, resolved.Val)
+			rootPaths = append(rootPaths, p)
+		}
+	}
 
-	return JSONDocument{Val: val}, nil
+	var matches []string
+	for i, root := range roots {
+		searchJSONValue(root, rootPaths[i], pattern, all, &matches)
+		if !all && len(matches) > 0 {
+			break
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	arr := make([]interface{}, len(matches))
+	for i, m := range matches {
+		arr[i] = m
+	}
+	return JSONDocument{Val: arr}, nil
 }
 
-func (doc JSONDocument) Keys(ctx *sql.Context, path string) (val JSONValue, err error) {
-	panic("not implemented")
+// searchJSONValue walks val depth-first (object keys in sorted order, then
+// array elements in order), appending the path of every matching string
+// scalar to matches. If !all, it stops as soon as one match is found.
+func searchJSONValue(val interface{}, path string, pattern *likePattern, all bool, matches *[]string) {
+	if !all && len(*matches) > 0 {
+		return
+	}
+	switch v := val.(type) {
+	case string:
+		if pattern.match(v) {
+			*matches = append(*matches, path)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			searchJSONValue(v[k], jsonPathAppendKey(path, k), pattern, all, matches)
+			if !all && len(*matches) > 0 {
+				return
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			searchJSONValue(e, fmt.Sprintf("%s[%d]", path, i), pattern, all, matches)
+			if !all && len(*matches) > 0 {
+				return
+			}
+		}
+	}
 }
 
-func (doc JSONDocument) Overlaps(ctx *sql.Context, val SearchableJSONValue) (ok bool, err error) {
-	panic("not implemented")
+// jsonPathAppendKey appends a `.key` segment to a JSON path, quoting the
+// key if it's not a valid bare identifier.
+func jsonPathAppendKey(path, key string) string {
+	bare := key != "" && !strings.ContainsAny(key, " \t.[]\"'")
+	if bare {
+		return path + "." + key
+	}
+	return path + `."` + key + `"`
 }
 
-func (doc JSONDocument) Search(ctx *sql.Context) (path string, err error) {
-	panic("not implemented")
+// likePattern is a compiled SQL LIKE pattern (`%` matches any run of
+// characters, `_` matches exactly one, both escapable) as used by
+// JSON_SEARCH's searchStr argument.
+type likePattern struct {
+	re *regexp.Regexp
+}
+
+// compileJSONSearchPattern translates a LIKE-style pattern into a regular
+// expression. escape is the single escape character to use in place of the
+// default `\`, or "" for the default.
+func compileJSONSearchPattern(pattern, escape string) (*likePattern, error) {
+	esc := byte('\\')
+	if escape != "" {
+		esc = escape[0]
+	}
+
+	var b strings.Builder
+	b.WriteByte('^')
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c == esc && i+1 < len(pattern) {
+			b.WriteString(regexp.QuoteMeta(string(pattern[i+1])))
+			i++
+			continue
+		}
+		switch c {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteByte('$')
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, err
+	}
+	return &likePattern{re: re}, nil
+}
+
+func (p *likePattern) match(s string) bool {
+	return p.re.MatchString(s)
 }
 
 var _ driver.Valuer = JSONDocument{}
@@ -570,15 +950,66 @@ func (doc JSONDocument) Insert(ctx *sql.Context, path string, val JSONValue) (Mu
 		// Do nothing. Can't replace the root object
 		return doc, false, nil
 	}
-	return doc.needANameForThis(ctx, path, val, INSERT)
+	return doc.mutateAtPath(ctx, path, val, INSERT)
 }
 
+// Remove walks path with the jsonpath package down to the parent of its
+// final segment and deletes the object key or array element found there,
+// reporting removed=false (doc returned unchanged) if any segment along the
+// way doesn't resolve - JSON_REMOVE is a no-op for a path that doesn't
+// exist, not an error.
 func (doc JSONDocument) Remove(ctx *sql.Context, path string) (MutableJSONValue, bool, error) {
+	path = strings.TrimSpace(path)
 	if path == "$" {
 		return nil, false, fmt.Errorf("The path expression '$' is not allowed in this context.")
 	}
 
-	panic("implement me")
+	parent, last, err := splitMutationPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	newVal, removed, err := walkMutate(doc.Val, parent, func(container interface{}) (interface{}, bool, error) {
+		switch last.Kind {
+		case jsonpath.Member:
+			obj, ok := container.(map[string]interface{})
+			if !ok {
+				return container, false, nil
+			}
+			if _, ok := obj[last.Name]; !ok {
+				return container, false, nil
+			}
+			newObj := make(map[string]interface{}, len(obj)-1)
+			for k, v := range obj {
+				if k != last.Name {
+					newObj[k] = v
+				}
+			}
+			return newObj, true, nil
+		case jsonpath.Index:
+			arr, ok := container.([]interface{})
+			if !ok {
+				return container, false, nil
+			}
+			idx := resolveIndex(last, len(arr))
+			if idx < 0 || idx >= len(arr) {
+				return container, false, nil
+			}
+			newArr := make([]interface{}, 0, len(arr)-1)
+			newArr = append(newArr, arr[:idx]...)
+			newArr = append(newArr, arr[idx+1:]...)
+			return newArr, true, nil
+		default:
+			return container, false, fmt.Errorf("a wildcard is not allowed in a removal path")
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !removed {
+		return doc, false, nil
+	}
+	return JSONDocument{Val: newVal}, true, nil
 }
 
 func (doc JSONDocument) Set(ctx *sql.Context, path string, val JSONValue) (MutableJSONValue, bool, error) {
@@ -592,7 +1023,7 @@ func (doc JSONDocument) Set(ctx *sql.Context, path string, val JSONValue) (Mutab
 		return res, true, nil
 	}
 
-	return doc.needANameForThis(ctx, path, val, SET)
+	return doc.mutateAtPath(ctx, path, val, SET)
 }
 
 func (doc JSONDocument) Replace(ctx *sql.Context, path string, val JSONValue) (MutableJSONValue, bool, error) {
@@ -606,7 +1037,7 @@ func (doc JSONDocument) Replace(ctx *sql.Context, path string, val JSONValue) (M
 		return res, true, nil
 	}
 
-	return doc.needANameForThis(ctx, path, val, REPLACE)
+	return doc.mutateAtPath(ctx, path, val, REPLACE)
 }
 
 const (
@@ -616,162 +1047,230 @@ const (
 	REMOVE
 )
 
-func (doc JSONDocument) needANameForThis(ctx *sql.Context, path string, val JSONValue, mode int) (MutableJSONValue, bool, error) {
-
-	path = path[1:]
-
-	unmarshalled, err := val.Unmarshall(ctx)
+// MergePatch implements JSON_MERGE_PATCH's RFC 7396 semantics.
+//
+// https://dev.mysql.com/doc/refman/8.0/en/json-modification-functions.html#function_json-merge-patch
+// https://www.rfc-editor.org/rfc/rfc7396
+func (doc JSONDocument) MergePatch(ctx *sql.Context, patch JSONValue) (MutableJSONValue, bool, error) {
+	p, err := patch.Unmarshall(ctx)
 	if err != nil {
-		panic("whay??? NM4")
+		return nil, false, err
 	}
+	merged := mergePatch(doc.Val, p.Val)
+	return JSONDocument{Val: merged}, true, nil
+}
 
-	if path[0] == '.' {
-		strMap, ok := doc.Val.(map[string]interface{})
-		if !ok {
-			panic("wasn't a map? NM4")
-		}
+// mergePatch is the recursive core of RFC 7396 merge-patch application.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		// A non-object patch (or patch of null) replaces target outright.
+		return patch
+	}
 
-		name := path[1:]
-		if name == "" {
-			panic("invalid path")
-		} else if name[0] == '"' {
-			// find the next quote
-			right := strings.Index(name[1:], "\"")
-			if right == -1 {
-				panic("invalid path")
-			}
-			name = name[1 : right+1]
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		// Merging an object patch onto a non-object target discards the
+		// target and starts from an empty object.
+		targetObj = map[string]interface{}{}
+	} else {
+		// Don't mutate the caller's map in place.
+		copied := make(map[string]interface{}, len(targetObj))
+		for k, v := range targetObj {
+			copied[k] = v
 		}
+		targetObj = copied
+	}
 
-		// does the name exist in the map?
-		updated := false
-		_, destrutive := strMap[name]
-		if mode == SET ||
-			(!destrutive && mode == INSERT) ||
-			(destrutive && mode == REPLACE) {
-			strMap[name] = unmarshalled.Val
-			updated = true
-		}
-		return doc, updated, nil
-	} else if path[0] == '[' {
-		right := strings.Index(path, "]")
-		if right == -1 {
-			panic("invalid path")
+	for key, patchVal := range patchObj {
+		if patchVal == nil {
+			delete(targetObj, key)
+			continue
 		}
+		targetObj[key] = mergePatch(targetObj[key], patchVal)
+	}
+	return targetObj
+}
 
-		if arr, ok := doc.Val.([]interface{}); ok {
-			index, err := parseIndex(path[1:right], len(arr)-1)
-			if err != nil {
-				panic("invalid path - index is not a number")
-			}
+// splitMutationPath parses path and splits it into the segments leading to
+// the parent of the final step (parent) and the final step itself (last),
+// for the shared use of Insert/Set/Replace/Remove. Wildcards aren't
+// meaningful as a mutation target, so a path containing one is rejected.
+func splitMutationPath(path string) (parent []jsonpath.PathSegment, last jsonpath.PathSegment, err error) {
+	p, err := jsonpath.ParsePath(path)
+	if err != nil {
+		return nil, jsonpath.PathSegment{}, err
+	}
+	if p.HasWildcard() {
+		return nil, jsonpath.PathSegment{}, fmt.Errorf("a wildcard is not allowed in a mutation path")
+	}
 
-			if index.underflow && (mode == INSERT || mode == REPLACE) {
-				return doc, false, nil
-			}
+	segs := []jsonpath.PathSegment(p)
+	if len(segs) > 0 && segs[0].Kind == jsonpath.Root {
+		segs = segs[1:]
+	}
+	if len(segs) == 0 {
+		return nil, jsonpath.PathSegment{}, fmt.Errorf("the path expression '$' is not allowed in this context")
+	}
+	return segs[:len(segs)-1], segs[len(segs)-1], nil
+}
 
-			if len(arr) > index.index && !index.overflow {
-				updated := false
-				if mode == SET || mode == REPLACE {
-					arr[index.index] = unmarshalled.Val
-					updated = true
-				}
-				return doc, updated, nil
-			} else {
-				if mode == SET || mode == INSERT {
-					newArr := append(arr, unmarshalled.Val)
-					return JSONDocument{Val: newArr}, true, nil
-				}
-				return doc, false, nil
-			}
-		} else {
-			// We don't have an array, so must be a scalar or an object that the user is treating as an array. Thankfully
-			// MySQL treats both the same way, but it's a little nutty nonetheless.
-			index, err := parseIndex(path[1:right], 0)
-			if err != nil {
-				panic("invalid path - index is not a number")
-			}
+// resolveIndex turns an Index segment (which may be relative to the end of
+// the array, via FromEnd/Offset) into a concrete, possibly out-of-range,
+// index against an array of the given length.
+func resolveIndex(seg jsonpath.PathSegment, length int) int {
+	if !seg.FromEnd {
+		return seg.Index
+	}
+	return length - 1 - seg.Offset
+}
 
-			if !index.underflow {
-				if index.index == 0 && !index.overflow {
-					if mode == SET || mode == REPLACE {
-						return JSONDocument{Val: unmarshalled.Val}, true, nil
-					}
-					return doc, false, nil
-				} else {
-					if index.overflow && (mode == SET || mode == INSERT) {
-						var newArr = make([]interface{}, 0, 2)
-						newArr = append(newArr, doc.Val)
-						newArr = append(newArr, unmarshalled.Val)
-						return JSONDocument{Val: newArr}, true, nil
-					}
-					return doc, false, nil
-				}
-			} else {
-				if mode == SET || mode == INSERT {
-					// convert to an array, [val, object]
-					var newArr = make([]interface{}, 0, 2)
-					newArr = append(newArr, unmarshalled.Val)
-					newArr = append(newArr, doc.Val)
-					return JSONDocument{Val: newArr}, true, nil
-				}
-				return doc, false, nil
-			}
+// walkMutate walks val along path, and once path is exhausted, lets mutate
+// transform the container path addresses - the parent of whatever a
+// mutation's final path segment will act on. Like mergePatch, it copies
+// only the containers it touches, rather than mutating val in place.
+// mutate reports whether it actually changed anything; when it (or a
+// deeper call) doesn't, val is returned unchanged.
+func walkMutate(val interface{}, path []jsonpath.PathSegment, mutate func(container interface{}) (interface{}, bool, error)) (interface{}, bool, error) {
+	if len(path) == 0 {
+		return mutate(val)
+	}
+	seg, rest := path[0], path[1:]
+
+	switch seg.Kind {
+	case jsonpath.Member:
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return val, false, nil
+		}
+		child, ok := obj[seg.Name]
+		if !ok {
+			return val, false, nil
+		}
+		newChild, changed, err := walkMutate(child, rest, mutate)
+		if err != nil || !changed {
+			return val, changed, err
+		}
+		newObj := make(map[string]interface{}, len(obj))
+		for k, v := range obj {
+			newObj[k] = v
 		}
+		newObj[seg.Name] = newChild
+		return newObj, true, nil
 
-	} else {
-		panic("invalid path")
+	case jsonpath.Index:
+		arr, ok := val.([]interface{})
+		if !ok {
+			return val, false, nil
+		}
+		idx := resolveIndex(seg, len(arr))
+		if idx < 0 || idx >= len(arr) {
+			return val, false, nil
+		}
+		newChild, changed, err := walkMutate(arr[idx], rest, mutate)
+		if err != nil || !changed {
+			return val, changed, err
+		}
+		newArr := make([]interface{}, len(arr))
+		copy(newArr, arr)
+		newArr[idx] = newChild
+		return newArr, true, nil
+
+	default:
+		return val, false, fmt.Errorf("a wildcard is not allowed in a mutation path")
 	}
 }
 
-type parseIndexResult struct {
-	underflow bool
-	overflow  bool
-	index     int
-}
+// mutateAtPath is the shared implementation behind Insert/Set/Replace: it
+// walks path down to the parent of its final segment and applies mode's
+// SET/INSERT/REPLACE semantics there via applyMutation. path == "$" is
+// handled by the caller before reaching here.
+func (doc JSONDocument) mutateAtPath(ctx *sql.Context, path string, val JSONValue, mode int) (MutableJSONValue, bool, error) {
+	parent, last, err := splitMutationPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	unmarshalled, err := val.Unmarshall(ctx)
+	if err != nil {
+		return nil, false, err
+	}
 
-func parseIndex(index string, lastIndex int) (parseIndexResult, error) {
-	// trim whitespace off the ends
-	index = strings.TrimSpace(index)
+	newVal, changed, err := walkMutate(doc.Val, parent, func(container interface{}) (interface{}, bool, error) {
+		return applyMutation(container, last, unmarshalled.Val, mode)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !changed {
+		return doc, false, nil
+	}
+	return JSONDocument{Val: newVal}, true, nil
+}
 
-	if index == "last" {
-		if lastIndex < 0 {
-			lastIndex = 0 // This happens for an empty array
+// applyMutation applies mode (SET/INSERT/REPLACE) to the single member or
+// element last addresses within container, following MySQL's rule that
+// indexing into a scalar or object treats it as a single-element array
+// (https://dev.mysql.com/doc/refman/8.0/en/json.html#json-path-syntax).
+func applyMutation(container interface{}, last jsonpath.PathSegment, val interface{}, mode int) (interface{}, bool, error) {
+	switch last.Kind {
+	case jsonpath.Member:
+		obj, ok := container.(map[string]interface{})
+		if !ok {
+			return container, false, fmt.Errorf("cannot set a member of a non-object JSON value")
 		}
-		return parseIndexResult{index: lastIndex}, nil
-	} else {
-		// split the string on "-"
-		parts := strings.Split(index, "-")
-		if len(parts) == 2 {
-			part1, part2 := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
-			if part1 == "last" {
-				lastMinus, err := strconv.Atoi(part2)
-				if err != nil || lastMinus < 0 {
-					panic("pan't parse index")
-				}
+		_, exists := obj[last.Name]
+		if mode == SET || (!exists && mode == INSERT) || (exists && mode == REPLACE) {
+			newObj := make(map[string]interface{}, len(obj)+1)
+			for k, v := range obj {
+				newObj[k] = v
+			}
+			newObj[last.Name] = val
+			return newObj, true, nil
+		}
+		return container, false, nil
 
-				underFlow := false
-				reducedIdx := lastIndex - lastMinus
-				if reducedIdx < 0 {
-					reducedIdx = 0
-					underFlow = true
+	case jsonpath.Index:
+		if arr, ok := container.([]interface{}); ok {
+			idx := resolveIndex(last, len(arr))
+			if idx >= 0 && idx < len(arr) {
+				if mode == SET || mode == REPLACE {
+					newArr := make([]interface{}, len(arr))
+					copy(newArr, arr)
+					newArr[idx] = val
+					return newArr, true, nil
 				}
-				return parseIndexResult{index: reducedIdx, underflow: underFlow}, nil
-			} else {
-				panic("pan't parse index")
+				return container, false, nil
 			}
+			if mode == SET || mode == INSERT {
+				newArr := make([]interface{}, len(arr), len(arr)+1)
+				copy(newArr, arr)
+				newArr = append(newArr, val)
+				return newArr, true, nil
+			}
+			return container, false, nil
 		}
-	}
 
-	val, err := strconv.Atoi(index)
-	if err != nil {
-		panic("pan't parse index")
-	}
+		// container is a scalar or object: MySQL treats it as a
+		// single-element array for indexing purposes. Any index other
+		// than 0 falls outside that virtual array; FromEnd (last/last-N)
+		// puts it before index 0 rather than after.
+		idx := resolveIndex(last, 1)
+		if idx == 0 {
+			if mode == SET || mode == REPLACE {
+				return val, true, nil
+			}
+			return container, false, nil
+		}
+		if mode != SET && mode != INSERT {
+			return container, false, nil
+		}
+		if idx < 0 {
+			return []interface{}{val, container}, true, nil
+		}
+		return []interface{}{container, val}, true, nil
 
-	overflow := false
-	if val > lastIndex {
-		val = lastIndex
-		overflow = true
+	default:
+		return container, false, fmt.Errorf("a wildcard is not allowed in a mutation path")
 	}
-
-	return parseIndexResult{index: val, overflow: overflow}, nil
 }
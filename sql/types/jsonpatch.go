@@ -0,0 +1,335 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// ApplyPatch applies an RFC 6902 JSON Patch document to doc, implementing
+// JSON_PATCH(). patch must Unmarshall to a JSON array of operation objects
+// (`add`, `remove`, `replace`, `move`, `copy`, `test`); each is applied in
+// order against the result of the previous one. Unlike Insert/Set/Replace/
+// Remove, which address a target with a MySQL-style `$.foo[0]` path, patch
+// operations address it with an RFC 6901 JSON Pointer (`/foo/0`).
+//
+// A failing `test` operation does not abort the patch or return an error -
+// the remaining operations still apply - but it's reflected in the second
+// return value, which is the AND of every `test` op's result (true if the
+// document contained no `test` operations at all).
+//
+// https://www.rfc-editor.org/rfc/rfc6902
+// https://www.rfc-editor.org/rfc/rfc6901
+func (doc JSONDocument) ApplyPatch(ctx *sql.Context, patch JSONValue) (MutableJSONValue, bool, error) {
+	p, err := patch.Unmarshall(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	ops, ok := p.Val.([]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf("a JSON patch document must be an array of operations")
+	}
+
+	result := doc.Val
+	testsPassed := true
+
+	for _, rawOp := range ops {
+		opObj, ok := rawOp.(map[string]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf("a JSON patch operation must be an object")
+		}
+		opName, _ := opObj["op"].(string)
+		pathStr, _ := opObj["path"].(string)
+
+		pointer, err := parseJSONPointer(pathStr)
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch opName {
+		case "add":
+			result, err = jsonPointerAdd(result, pointer, opObj["value"])
+		case "remove":
+			var removed bool
+			result, removed, err = jsonPointerRemove(result, pointer)
+			if err == nil && !removed {
+				err = fmt.Errorf("JSON patch 'remove': path %q does not exist", pathStr)
+			}
+		case "replace":
+			result, err = jsonPointerReplace(result, pointer, opObj["value"])
+		case "move", "copy":
+			fromStr, _ := opObj["from"].(string)
+			var fromPtr []string
+			fromPtr, err = parseJSONPointer(fromStr)
+			if err != nil {
+				break
+			}
+			var val interface{}
+			val, err = jsonPointerGet(result, fromPtr)
+			if err != nil {
+				break
+			}
+			if opName == "move" {
+				result, _, err = jsonPointerRemove(result, fromPtr)
+				if err != nil {
+					break
+				}
+			}
+			result, err = jsonPointerAdd(result, pointer, val)
+		case "test":
+			var val interface{}
+			val, err = jsonPointerGet(result, pointer)
+			if err != nil {
+				testsPassed = false
+				err = nil
+				break
+			}
+			cmp, cmpErr := compareJSON(val, opObj["value"])
+			if cmpErr != nil || cmp != 0 {
+				testsPassed = false
+			}
+		default:
+			return nil, false, fmt.Errorf("unsupported JSON patch operation %q", opName)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return JSONDocument{Val: result}, testsPassed, nil
+}
+
+// parseJSONPointer splits an RFC 6901 JSON Pointer into its reference
+// tokens, unescaping `~1` to `/` and `~0` to `~` in that order (reversing
+// the escaping order the RFC specifies for encoding). The empty pointer ""
+// addresses the whole document and parses to a nil/empty token slice.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("a JSON Pointer must be empty or start with '/', got %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		t = strings.ReplaceAll(t, "~1", "/")
+		t = strings.ReplaceAll(t, "~0", "~")
+		tokens[i] = t
+	}
+	return tokens, nil
+}
+
+// jsonPointerGet resolves a JSON Pointer against root and returns the value
+// found there. The `-` end-of-array token is not a valid read target (RFC
+// 6901 only gives it meaning for `add`) and is rejected.
+func jsonPointerGet(root interface{}, tokens []string) (interface{}, error) {
+	cur := root
+	for _, t := range tokens {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[t]
+			if !ok {
+				return nil, fmt.Errorf("JSON Pointer: object has no member %q", t)
+			}
+			cur = v
+		case []interface{}:
+			idx, err := jsonPointerArrayIndex(t, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = c[idx]
+		default:
+			return nil, fmt.Errorf("JSON Pointer: cannot descend into a scalar at %q", t)
+		}
+	}
+	return cur, nil
+}
+
+// jsonPointerArrayIndex parses a JSON Pointer array token, accepting "-" as
+// the one-past-the-end index when allowAppend is true (used by `add`).
+func jsonPointerArrayIndex(token string, length int, allowAppend bool) (int, error) {
+	if token == "-" {
+		if allowAppend {
+			return length, nil
+		}
+		return 0, fmt.Errorf("JSON Pointer: '-' is not a valid array index here")
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || idx > length || (!allowAppend && idx >= length) {
+		return 0, fmt.Errorf("JSON Pointer: array index %q out of range", token)
+	}
+	return idx, nil
+}
+
+// jsonPointerMutate walks root along path to the container the pointer's
+// parent addresses, rebuilds every container on the way back up (so
+// unrelated parts of the tree are shared, not copied), and lets mutate
+// transform the container the final token lives in.
+func jsonPointerMutate(root interface{}, path []string, mutate func(container interface{}) (interface{}, error)) (interface{}, error) {
+	if len(path) == 0 {
+		return mutate(root)
+	}
+	head, rest := path[0], path[1:]
+
+	switch c := root.(type) {
+	case map[string]interface{}:
+		child, ok := c[head]
+		if !ok {
+			return nil, fmt.Errorf("JSON Pointer: object has no member %q", head)
+		}
+		newChild, err := jsonPointerMutate(child, rest, mutate)
+		if err != nil {
+			return nil, err
+		}
+		newObj := make(map[string]interface{}, len(c))
+		for k, v := range c {
+			newObj[k] = v
+		}
+		newObj[head] = newChild
+		return newObj, nil
+	case []interface{}:
+		idx, err := jsonPointerArrayIndex(head, len(c), false)
+		if err != nil {
+			return nil, err
+		}
+		newChild, err := jsonPointerMutate(c[idx], rest, mutate)
+		if err != nil {
+			return nil, err
+		}
+		newArr := make([]interface{}, len(c))
+		copy(newArr, c)
+		newArr[idx] = newChild
+		return newArr, nil
+	default:
+		return nil, fmt.Errorf("JSON Pointer: cannot descend into a scalar at %q", head)
+	}
+}
+
+// jsonPointerAdd implements RFC 6902 `add`: inserting into an array shifts
+// later elements right rather than overwriting, and `path` being the root
+// pointer ("") replaces the whole document with value.
+func jsonPointerAdd(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, last := path[:len(path)-1], path[len(path)-1]
+
+	return jsonPointerMutate(root, parent, func(container interface{}) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			newObj := make(map[string]interface{}, len(c)+1)
+			for k, v := range c {
+				newObj[k] = v
+			}
+			newObj[last] = value
+			return newObj, nil
+		case []interface{}:
+			idx, err := jsonPointerArrayIndex(last, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			newArr := make([]interface{}, 0, len(c)+1)
+			newArr = append(newArr, c[:idx]...)
+			newArr = append(newArr, value)
+			newArr = append(newArr, c[idx:]...)
+			return newArr, nil
+		default:
+			return nil, fmt.Errorf("JSON Pointer: cannot add into a scalar")
+		}
+	})
+}
+
+// jsonPointerReplace implements RFC 6902 `replace`: the target member or
+// element must already exist.
+func jsonPointerReplace(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, last := path[:len(path)-1], path[len(path)-1]
+
+	return jsonPointerMutate(root, parent, func(container interface{}) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			if _, ok := c[last]; !ok {
+				return nil, fmt.Errorf("JSON Pointer: object has no member %q", last)
+			}
+			newObj := make(map[string]interface{}, len(c))
+			for k, v := range c {
+				newObj[k] = v
+			}
+			newObj[last] = value
+			return newObj, nil
+		case []interface{}:
+			idx, err := jsonPointerArrayIndex(last, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			newArr := make([]interface{}, len(c))
+			copy(newArr, c)
+			newArr[idx] = value
+			return newArr, nil
+		default:
+			return nil, fmt.Errorf("JSON Pointer: cannot replace into a scalar")
+		}
+	})
+}
+
+// jsonPointerRemove implements RFC 6902 `remove`. If the pointer's parent
+// or its target doesn't resolve, it returns root unchanged, removed=false,
+// and an error.
+func jsonPointerRemove(root interface{}, path []string) (interface{}, bool, error) {
+	if len(path) == 0 {
+		return nil, false, fmt.Errorf("JSON Pointer: cannot remove the whole document")
+	}
+	parent, last := path[:len(path)-1], path[len(path)-1]
+
+	removed := false
+	newRoot, err := jsonPointerMutate(root, parent, func(container interface{}) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			if _, ok := c[last]; !ok {
+				return nil, fmt.Errorf("JSON Pointer: object has no member %q", last)
+			}
+			newObj := make(map[string]interface{}, len(c)-1)
+			for k, v := range c {
+				if k != last {
+					newObj[k] = v
+				}
+			}
+			removed = true
+			return newObj, nil
+		case []interface{}:
+			idx, err := jsonPointerArrayIndex(last, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			newArr := make([]interface{}, 0, len(c)-1)
+			newArr = append(newArr, c[:idx]...)
+			newArr = append(newArr, c[idx+1:]...)
+			removed = true
+			return newArr, nil
+		default:
+			return nil, fmt.Errorf("JSON Pointer: cannot remove from a scalar")
+		}
+	})
+	if err != nil {
+		return root, false, err
+	}
+	return newRoot, removed, nil
+}